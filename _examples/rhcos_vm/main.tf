@@ -1,7 +1,5 @@
 provider "kubevirt" {
 }
-provider "kubernetes" {
-}
 
 locals {
   namespace       = "terraform-provider-kubevirt-demo"
@@ -47,23 +45,6 @@ data "ignition_file" "hostname" {
   }
 }
 
-// Create the secret which holds the ignition data
-
-resource "kubernetes_secret" "vm_ignition" {
-  count = local.vm_count
-
-  metadata {
-    name      = "${local.vm_name_preffix}-${count.index}-ignition"
-    namespace = local.namespace
-  }
-  data = {
-    "userdata" = element(
-      data.ignition_config.vm_ignition_config.*.rendered,
-      count.index,
-    )
-  }
-}
-
 // Create the source data volume that all VMs should be cloned from
 
 resource "kubevirt_data_volume" "data_volume" {
@@ -142,10 +123,14 @@ resource "kubevirt_virtual_machine" "virtual_machine" {
         volume {
           name = "${local.vm_name_preffix}-cloudinitdisk-${count.index}"
           volume_source {
+            // The ignition exceeds what KubeVirt accepts inline, so the provider
+            // stores it in a secret named after the VM.
             cloud_init_config_drive {
-              user_data_secret_ref {
-                name = kubernetes_secret.vm_ignition[count.index].metadata.0.name
-              }
+              user_data = element(
+                data.ignition_config.vm_ignition_config.*.rendered,
+                count.index,
+              )
+              store_in_secret = true
             }
           }
         }
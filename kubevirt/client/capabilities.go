@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Component is a cluster add-on whose version and feature gates the provider depends on.
+type Component string
+
+const (
+	KubeVirt Component = "KubeVirt"
+	CDI      Component = "CDI"
+)
+
+// supportedVersions lists, per API group, the versions the provider is able to talk to,
+// in order of preference. KubeVirt serves v1alpha3 with the same schema as v1, for clusters
+// that predate v1. The CDI v1alpha1 schema differs from v1beta1 and is not supported.
+var supportedVersions = map[string][]string{
+	kubevirtapiv1.GroupVersion.Group: {kubevirtapiv1.GroupVersion.Version, "v1alpha3"},
+	cdiv1.SchemeGroupVersion.Group:   {cdiv1.SchemeGroupVersion.Version},
+}
+
+// Feature describes a capability of KubeVirt or CDI that is only available from a given
+// version on, or only when a feature gate is enabled.
+type Feature struct {
+	// Name is how the feature is referred to in messages.
+	Name      string
+	Component Component
+	// MinVersion is the first version of the component supporting the feature, if any.
+	MinVersion string
+	// FeatureGate is the feature gate that has to be enabled for the feature, if any.
+	FeatureGate string
+	// GraduatedIn is the first version of the component in which the feature gate is no longer
+	// needed, if known.
+	GraduatedIn string
+}
+
+// featureGatesUnverifiedFrom is, per component, the first version newer than the API the provider
+// is built against. Feature gates may have graduated since, and graduated gates are no longer
+// listed in the configuration, so a missing gate is only a warning from these versions on.
+var featureGatesUnverifiedFrom = map[Component]string{
+	KubeVirt: "0.60",
+	CDI:      "1.57",
+}
+
+type componentInfo struct {
+	group        string
+	groupVersion string
+	// known is false when the component's CR could not be read.
+	known        bool
+	version      *version.Version
+	featureGates map[string]bool
+}
+
+// Capabilities describes what the target cluster supports: the served API versions, and the
+// versions and enabled feature gates of KubeVirt and CDI. It is discovered on first use and
+// cached on the client.
+type Capabilities struct {
+	components map[Component]*componentInfo
+}
+
+// Installed reports whether the API group of the component is served with a version the provider supports.
+func (c *Capabilities) Installed(component Component) bool {
+	info, ok := c.components[component]
+	return ok && info.groupVersion != ""
+}
+
+// Check verifies that the cluster supports the given feature. It returns an error when the
+// feature is known to be unsupported, and a warning when support could not be determined.
+func (c *Capabilities) Check(feature Feature) (string, error) {
+	if !c.Installed(feature.Component) {
+		return "", fmt.Errorf("%s requires %s, which is not installed in the cluster", feature.Name, feature.Component)
+	}
+	info := c.components[feature.Component]
+	if feature.MinVersion == "" && feature.FeatureGate == "" {
+		return "", nil
+	}
+	if !info.known {
+		return fmt.Sprintf("unable to verify that %s supports %s, the %s configuration could not be read", feature.Component, feature.Name, feature.Component), nil
+	}
+	if feature.MinVersion != "" {
+		if info.version == nil {
+			return fmt.Sprintf("unable to verify that %s supports %s, its version is unknown", feature.Component, feature.Name), nil
+		}
+		if info.version.LessThan(version.MustParseGeneric(feature.MinVersion)) {
+			return "", fmt.Errorf("%s requires %s %s or newer, but the cluster runs %s", feature.Name, feature.Component, feature.MinVersion, info.version)
+		}
+	}
+	if feature.FeatureGate != "" && !info.featureGates[feature.FeatureGate] {
+		return c.checkMissingFeatureGate(feature, info)
+	}
+	return "", nil
+}
+
+// checkMissingFeatureGate reports a feature gate that is not enabled in the configuration, taking
+// into account that it may have graduated in the version the cluster runs.
+func (c *Capabilities) checkMissingFeatureGate(feature Feature, info *componentInfo) (string, error) {
+	if info.version == nil {
+		return fmt.Sprintf("unable to verify that the %s feature gate %q needed by %s is enabled, the %s version is unknown",
+			feature.Component, feature.FeatureGate, feature.Name, feature.Component), nil
+	}
+	if feature.GraduatedIn != "" && !info.version.LessThan(version.MustParseGeneric(feature.GraduatedIn)) {
+		return "", nil
+	}
+	if from, ok := featureGatesUnverifiedFrom[feature.Component]; ok && !info.version.LessThan(version.MustParseGeneric(from)) {
+		return fmt.Sprintf("%s may require the %s feature gate %q, which is not enabled; this is expected if the feature gate has graduated in %s %s",
+			feature.Name, feature.Component, feature.FeatureGate, feature.Component, info.version), nil
+	}
+	return "", fmt.Errorf("%s requires the %s feature gate %q to be enabled", feature.Name, feature.Component, feature.FeatureGate)
+}
+
+func (c *Capabilities) resource(component Component, resource string) (schema.GroupVersionResource, error) {
+	if !c.Installed(component) {
+		group := c.components[component].group
+		return schema.GroupVersionResource{}, fmt.Errorf("the cluster does not serve %s with version %s, is %s installed?",
+			group, strings.Join(supportedVersions[group], " or "), component)
+	}
+	info := c.components[component]
+	gv, err := schema.ParseGroupVersion(info.groupVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return gv.WithResource(resource), nil
+}
+
+// defaultCapabilities assumes the API versions the provider was built against, for when discovery fails.
+func defaultCapabilities() *Capabilities {
+	return &Capabilities{
+		components: map[Component]*componentInfo{
+			KubeVirt: {group: kubevirtapiv1.GroupVersion.Group, groupVersion: kubevirtapiv1.GroupVersion.String()},
+			CDI:      {group: cdiv1.SchemeGroupVersion.Group, groupVersion: cdiv1.SchemeGroupVersion.String()},
+		},
+	}
+}
+
+// Capabilities discovers the capabilities of the cluster with the context of the caller. Only a
+// complete discovery is cached, so that transient failures are retried by the next caller.
+func (c *client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	c.capabilitiesMu.Lock()
+	defer c.capabilitiesMu.Unlock()
+
+	if c.capabilities != nil {
+		return c.capabilities, nil
+	}
+	capabilities, complete, err := c.discoverCapabilities(ctx)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, fmt.Sprintf("Failed to discover cluster capabilities, assuming %s and %s: %v",
+			kubevirtapiv1.GroupVersion, cdiv1.SchemeGroupVersion, err))
+		return nil, err
+	}
+	if complete {
+		c.capabilities = capabilities
+	}
+	return capabilities, nil
+}
+
+// resource resolves the GroupVersionResource to use for the given component's resource.
+// It falls back to the compiled-in API versions when discovery failed.
+func (c *client) resource(ctx context.Context, component Component, resource string) (schema.GroupVersionResource, error) {
+	capabilities, err := c.Capabilities(ctx)
+	if err != nil {
+		capabilities = defaultCapabilities()
+	}
+	return capabilities.resource(component, resource)
+}
+
+// discoverCapabilities discovers the capabilities of the cluster. It reports the result as
+// incomplete when a component configuration could not be read for a reason that may be transient.
+func (c *client) discoverCapabilities(ctx context.Context) (*Capabilities, bool, error) {
+	groups, err := c.discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := &Capabilities{
+		components: map[Component]*componentInfo{
+			KubeVirt: {group: kubevirtapiv1.GroupVersion.Group},
+			CDI:      {group: cdiv1.SchemeGroupVersion.Group},
+		},
+	}
+	for _, info := range result.components {
+		info.groupVersion = servedGroupVersion(groups, info.group)
+	}
+
+	complete := true
+	if result.Installed(KubeVirt) {
+		complete = c.readComponentConfig(ctx, result.components[KubeVirt], "kubevirts",
+			[]string{"status", "observedKubeVirtVersion"},
+			[]string{"spec", "configuration", "developerConfiguration", "featureGates"}) && complete
+	}
+	if result.Installed(CDI) {
+		complete = c.readComponentConfig(ctx, result.components[CDI], "cdis",
+			[]string{"status", "observedVersion"},
+			[]string{"spec", "config", "featureGates"}) && complete
+	}
+
+	for name, info := range result.components {
+		tflog.SubsystemDebug(ctx, utils.LogSubsystemClient, fmt.Sprintf("Discovered %s", name), map[string]interface{}{
+			"api_version":   info.groupVersion,
+			"version":       fmt.Sprint(info.version),
+			"feature_gates": fmt.Sprint(info.featureGates),
+		})
+	}
+	return result, complete, nil
+}
+
+// servedGroupVersion returns the first supported version of the group served by the cluster.
+func servedGroupVersion(groups *metav1.APIGroupList, group string) string {
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		for _, supported := range supportedVersions[group] {
+			for _, served := range g.Versions {
+				if served.Version == supported {
+					return served.GroupVersion
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// readComponentConfig reads the observed version and enabled feature gates from the operator CR
+// of a component. Failures are only logged, since users are not necessarily allowed to read the CR.
+// It returns false when the CR could not be read for a reason that may be transient.
+func (c *client) readComponentConfig(ctx context.Context, info *componentInfo, resource string, versionPath []string, featureGatesPath []string) bool {
+	gv, err := schema.ParseGroupVersion(info.groupVersion)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, fmt.Sprintf("Unable to parse %s: %v", info.groupVersion, err))
+		return true
+	}
+	list, err := c.dynamicClient.Resource(gv.WithResource(resource)).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, fmt.Sprintf("Unable to read %s: %v", resource, err))
+		return errors.IsForbidden(err) || errors.IsUnauthorized(err)
+	}
+	if len(list.Items) == 0 {
+		tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, fmt.Sprintf("No %s found in the cluster", resource))
+		return true
+	}
+
+	obj := list.Items[0].UnstructuredContent()
+	info.known = true
+	if observed, _, _ := unstructured.NestedString(obj, versionPath...); observed != "" {
+		if v, err := version.ParseGeneric(observed); err == nil {
+			info.version = v
+		} else {
+			tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, fmt.Sprintf("Unable to parse %s version %q: %v", resource, observed, err))
+		}
+	}
+	featureGates, _, _ := unstructured.NestedStringSlice(obj, featureGatesPath...)
+	info.featureGates = make(map[string]bool, len(featureGates))
+	for _, gate := range featureGates {
+		info.featureGates[gate] = true
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestCapabilitiesCheck(t *testing.T) {
+	capabilities := &Capabilities{
+		components: map[Component]*componentInfo{
+			KubeVirt: {
+				group:        "kubevirt.io",
+				groupVersion: "kubevirt.io/v1",
+				known:        true,
+				version:      version.MustParseGeneric("v0.59.0"),
+				featureGates: map[string]bool{"HotplugVolumes": true},
+			},
+			CDI: {
+				group:        "cdi.kubevirt.io",
+				groupVersion: "cdi.kubevirt.io/v1beta1",
+			},
+		},
+	}
+	newer := &Capabilities{
+		components: map[Component]*componentInfo{
+			KubeVirt: {
+				group:        "kubevirt.io",
+				groupVersion: "kubevirt.io/v1",
+				known:        true,
+				version:      version.MustParseGeneric("v1.1.0"),
+				featureGates: map[string]bool{},
+			},
+		},
+	}
+	versionUnknown := &Capabilities{
+		components: map[Component]*componentInfo{
+			KubeVirt: {
+				group:        "kubevirt.io",
+				groupVersion: "kubevirt.io/v1",
+				known:        true,
+				featureGates: map[string]bool{},
+			},
+		},
+	}
+	notInstalled := &Capabilities{
+		components: map[Component]*componentInfo{
+			KubeVirt: {group: "kubevirt.io"},
+			CDI:      {group: "cdi.kubevirt.io"},
+		},
+	}
+
+	testCases := []struct {
+		Name            string
+		Capabilities    *Capabilities
+		Feature         Feature
+		ExpectedWarning bool
+		ExpectedError   string
+	}{
+		{
+			Name:         "supported version and feature gate",
+			Capabilities: capabilities,
+			Feature:      Feature{Name: "hotplug", Component: KubeVirt, MinVersion: "0.58", FeatureGate: "HotplugVolumes"},
+		},
+		{
+			Name:          "version too old",
+			Capabilities:  capabilities,
+			Feature:       Feature{Name: "new feature", Component: KubeVirt, MinVersion: "1.0"},
+			ExpectedError: "new feature requires KubeVirt 1.0 or newer, but the cluster runs 0.59.0",
+		},
+		{
+			Name:          "feature gate disabled",
+			Capabilities:  capabilities,
+			Feature:       Feature{Name: "gpus", Component: KubeVirt, FeatureGate: "GPU"},
+			ExpectedError: "gpus requires the KubeVirt feature gate \"GPU\" to be enabled",
+		},
+		{
+			Name:         "feature gate graduated",
+			Capabilities: newer,
+			Feature:      Feature{Name: "gpus", Component: KubeVirt, FeatureGate: "GPU", GraduatedIn: "1.0"},
+		},
+		{
+			Name:            "feature gate possibly graduated",
+			Capabilities:    newer,
+			Feature:         Feature{Name: "gpus", Component: KubeVirt, FeatureGate: "GPU"},
+			ExpectedWarning: true,
+		},
+		{
+			Name:          "feature gate disabled before graduation",
+			Capabilities:  capabilities,
+			Feature:       Feature{Name: "gpus", Component: KubeVirt, FeatureGate: "GPU", GraduatedIn: "1.0"},
+			ExpectedError: "gpus requires the KubeVirt feature gate \"GPU\" to be enabled",
+		},
+		{
+			Name:            "feature gate missing with unknown version",
+			Capabilities:    versionUnknown,
+			Feature:         Feature{Name: "gpus", Component: KubeVirt, FeatureGate: "GPU"},
+			ExpectedWarning: true,
+		},
+		{
+			Name:            "configuration unknown",
+			Capabilities:    capabilities,
+			Feature:         Feature{Name: "storage", Component: CDI, MinVersion: "1.40"},
+			ExpectedWarning: true,
+		},
+		{
+			Name:          "not installed",
+			Capabilities:  notInstalled,
+			Feature:       Feature{Name: "kubevirt_data_volume", Component: CDI},
+			ExpectedError: "kubevirt_data_volume requires CDI, which is not installed in the cluster",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			warning, err := tc.Capabilities.Check(tc.Feature)
+			if tc.ExpectedError != "" {
+				if err == nil || err.Error() != tc.ExpectedError {
+					t.Fatalf("expected error %q, got %v", tc.ExpectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.ExpectedWarning != (warning != "") {
+				t.Fatalf("expected warning: %t, got %q", tc.ExpectedWarning, warning)
+			}
+		})
+	}
+}
+
+func TestServedGroupVersion(t *testing.T) {
+	group := func(name string, versions ...string) metav1.APIGroup {
+		g := metav1.APIGroup{Name: name}
+		for _, v := range versions {
+			g.Versions = append(g.Versions, metav1.GroupVersionForDiscovery{GroupVersion: name + "/" + v, Version: v})
+		}
+		return g
+	}
+
+	testCases := []struct {
+		Name     string
+		Groups   []metav1.APIGroup
+		Group    string
+		Expected string
+	}{
+		{
+			Name:     "preferred version served",
+			Groups:   []metav1.APIGroup{group("kubevirt.io", "v1alpha3", "v1")},
+			Group:    "kubevirt.io",
+			Expected: "kubevirt.io/v1",
+		},
+		{
+			Name:     "older version served",
+			Groups:   []metav1.APIGroup{group("kubevirt.io", "v1alpha3")},
+			Group:    "kubevirt.io",
+			Expected: "kubevirt.io/v1alpha3",
+		},
+		{
+			Name:   "unsupported version served",
+			Groups: []metav1.APIGroup{group("cdi.kubevirt.io", "v1alpha1")},
+			Group:  "cdi.kubevirt.io",
+		},
+		{
+			Name:   "group not served",
+			Groups: []metav1.APIGroup{group("kubevirt.io", "v1")},
+			Group:  "cdi.kubevirt.io",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			served := servedGroupVersion(&metav1.APIGroupList{Groups: tc.Groups}, tc.Group)
+			if served != tc.Expected {
+				t.Fatalf("expected %q, got %q", tc.Expected, served)
+			}
+		})
+	}
+}
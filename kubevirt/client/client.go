@@ -19,8 +19,11 @@ package client
 import (
 	"context"
 	"fmt"
-	"log"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	pkgApi "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	restclient "k8s.io/client-go/rest"
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
@@ -39,177 +43,268 @@ import (
 type Client interface {
 	// VirtualMachine CRUD operations
 
-	CreateVirtualMachine(vm *kubevirtapiv1.VirtualMachine) error
-	GetVirtualMachine(namespace string, name string) (*kubevirtapiv1.VirtualMachine, error)
-	UpdateVirtualMachine(namespace string, name string, vm *kubevirtapiv1.VirtualMachine, data []byte) error
-	DeleteVirtualMachine(namespace string, name string) error
+	CreateVirtualMachine(ctx context.Context, vm *kubevirtapiv1.VirtualMachine) error
+	GetVirtualMachine(ctx context.Context, namespace string, name string) (*kubevirtapiv1.VirtualMachine, error)
+	UpdateVirtualMachine(ctx context.Context, namespace string, name string, vm *kubevirtapiv1.VirtualMachine, data []byte) error
+	DeleteVirtualMachine(ctx context.Context, namespace string, name string) error
 
 	// DataVolume CRUD operations
 
-	CreateDataVolume(vm *cdiv1.DataVolume) error
-	GetDataVolume(namespace string, name string) (*cdiv1.DataVolume, error)
-	UpdateDataVolume(namespace string, name string, dv *cdiv1.DataVolume, data []byte) error
-	DeleteDataVolume(namespace string, name string) error
+	CreateDataVolume(ctx context.Context, vm *cdiv1.DataVolume) error
+	GetDataVolume(ctx context.Context, namespace string, name string) (*cdiv1.DataVolume, error)
+	UpdateDataVolume(ctx context.Context, namespace string, name string, dv *cdiv1.DataVolume, data []byte) error
+	DeleteDataVolume(ctx context.Context, namespace string, name string) error
+
+	// Secret CRUD operations
+
+	CreateSecret(ctx context.Context, secret *corev1.Secret) error
+	GetSecret(ctx context.Context, namespace string, name string) (*corev1.Secret, error)
+	UpdateSecret(ctx context.Context, namespace string, name string, secret *corev1.Secret, data []byte) error
+	DeleteSecret(ctx context.Context, namespace string, name string) error
+
+	// Capabilities returns what the target cluster supports. The result is discovered
+	// on first use and cached once discovery succeeds.
+	Capabilities(ctx context.Context) (*Capabilities, error)
 }
 
 type client struct {
-	dynamicClient dynamic.Interface
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
+
+	capabilitiesMu sync.Mutex
+	capabilities   *Capabilities
 }
 
 // New creates our client wrapper object for the actual kubeVirt and kubernetes clients we use.
-func NewClient(cfg *restclient.Config) (Client, error) {
+func NewClient(ctx context.Context, cfg *restclient.Config) (Client, error) {
 	result := &client{}
+	cfg = withAdaptiveRateLimiter(cfg)
 	c, err := dynamic.NewForConfig(cfg)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to create client, with error: %v", err)
-		log.Printf("[Error] %s", msg)
+		tflog.Error(ctx, msg)
 		return nil, fmt.Errorf(msg)
 	}
 	result.dynamicClient = c
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to create discovery client, with error: %v", err)
+		tflog.Error(ctx, msg)
+		return nil, fmt.Errorf(msg)
+	}
+	result.discoveryClient = dc
 	return result, nil
 }
 
 // VirtualMachine CRUD operations
 
-func (c *client) CreateVirtualMachine(vm *kubevirtapiv1.VirtualMachine) error {
-	vmUpdateTypeMeta(vm)
-	return c.createResource(vm, vm.Namespace, vmRes())
+func (c *client) CreateVirtualMachine(ctx context.Context, vm *kubevirtapiv1.VirtualMachine) error {
+	res, err := c.vmRes(ctx)
+	if err != nil {
+		return err
+	}
+	vmUpdateTypeMeta(vm, res)
+	return c.createResource(ctx, vm, vm.Namespace, res)
 }
 
-func (c *client) GetVirtualMachine(namespace string, name string) (*kubevirtapiv1.VirtualMachine, error) {
+func (c *client) GetVirtualMachine(ctx context.Context, namespace string, name string) (*kubevirtapiv1.VirtualMachine, error) {
 	var vm kubevirtapiv1.VirtualMachine
-	resp, err := c.getResource(namespace, name, vmRes())
+	res, err := c.vmRes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.getResource(ctx, namespace, name, res)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			log.Printf("[Warning] VirtualMachine %s not found (namespace=%s)", name, namespace)
+			tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, "VirtualMachine not found")
 			return nil, err
 		}
 		msg := fmt.Sprintf("Failed to get VirtualMachine, with error: %v", err)
-		log.Printf("[Error] %s", msg)
+		tflog.Error(ctx, msg)
 		return nil, fmt.Errorf(msg)
 	}
 	unstructured := resp.UnstructuredContent()
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured, &vm); err != nil {
 		msg := fmt.Sprintf("Failed to translate unstructed to VirtualMachine, with error: %v", err)
-		log.Printf("[Error] %s", msg)
+		tflog.Error(ctx, msg)
 		return nil, fmt.Errorf(msg)
 	}
 	return &vm, nil
 }
 
-func (c *client) UpdateVirtualMachine(namespace string, name string, vm *kubevirtapiv1.VirtualMachine, data []byte) error {
-	vmUpdateTypeMeta(vm)
-	return c.updateResource(namespace, name, vmRes(), vm, data)
+func (c *client) UpdateVirtualMachine(ctx context.Context, namespace string, name string, vm *kubevirtapiv1.VirtualMachine, data []byte) error {
+	res, err := c.vmRes(ctx)
+	if err != nil {
+		return err
+	}
+	vmUpdateTypeMeta(vm, res)
+	return c.updateResource(ctx, namespace, name, res, vm, data)
 }
 
-func (c *client) DeleteVirtualMachine(namespace string, name string) error {
-	return c.deleteResource(namespace, name, vmRes())
+func (c *client) DeleteVirtualMachine(ctx context.Context, namespace string, name string) error {
+	res, err := c.vmRes(ctx)
+	if err != nil {
+		return err
+	}
+	return c.deleteResource(ctx, namespace, name, res)
 }
 
-func vmUpdateTypeMeta(vm *kubevirtapiv1.VirtualMachine) {
+func vmUpdateTypeMeta(vm *kubevirtapiv1.VirtualMachine, res schema.GroupVersionResource) {
 	vm.TypeMeta = metav1.TypeMeta{
 		Kind:       "VirtualMachine",
-		APIVersion: kubevirtapiv1.GroupVersion.String(),
+		APIVersion: res.GroupVersion().String(),
 	}
 }
 
-func vmRes() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    kubevirtapiv1.GroupVersion.Group,
-		Version:  kubevirtapiv1.GroupVersion.Version,
-		Resource: "virtualmachines",
-	}
-
+func (c *client) vmRes(ctx context.Context) (schema.GroupVersionResource, error) {
+	return c.resource(ctx, KubeVirt, "virtualmachines")
 }
 
 // DataVolume CRUD operations
 
-func (c *client) CreateDataVolume(dv *cdiv1.DataVolume) error {
-	dvUpdateTypeMeta(dv)
-	return c.createResource(dv, dv.Namespace, dvRes())
+func (c *client) CreateDataVolume(ctx context.Context, dv *cdiv1.DataVolume) error {
+	res, err := c.dvRes(ctx)
+	if err != nil {
+		return err
+	}
+	dvUpdateTypeMeta(dv, res)
+	return c.createResource(ctx, dv, dv.Namespace, res)
 }
 
-func (c *client) GetDataVolume(namespace string, name string) (*cdiv1.DataVolume, error) {
+func (c *client) GetDataVolume(ctx context.Context, namespace string, name string) (*cdiv1.DataVolume, error) {
 	var dv cdiv1.DataVolume
-	resp, err := c.getResource(namespace, name, dvRes())
+	res, err := c.dvRes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.getResource(ctx, namespace, name, res)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			log.Printf("[Warning] DataVolume %s not found (namespace=%s)", name, namespace)
+			tflog.SubsystemWarn(ctx, utils.LogSubsystemClient, "DataVolume not found")
 			return nil, err
 		}
 		msg := fmt.Sprintf("Failed to get DataVolume, with error: %v", err)
-		log.Printf("[Error] %s", msg)
+		tflog.Error(ctx, msg)
 		return nil, fmt.Errorf(msg)
 	}
 	unstructured := resp.UnstructuredContent()
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured, &dv); err != nil {
 		msg := fmt.Sprintf("Failed to translate Unstructed to VirtualMachine, with error: %v", err)
-		log.Printf("[Error] %s", msg)
+		tflog.Error(ctx, msg)
 		return nil, fmt.Errorf(msg)
 	}
 	return &dv, nil
 }
 
-func (c *client) UpdateDataVolume(namespace string, name string, dv *cdiv1.DataVolume, data []byte) error {
-	dvUpdateTypeMeta(dv)
-	return c.updateResource(namespace, name, dvRes(), dv, data)
+func (c *client) UpdateDataVolume(ctx context.Context, namespace string, name string, dv *cdiv1.DataVolume, data []byte) error {
+	res, err := c.dvRes(ctx)
+	if err != nil {
+		return err
+	}
+	dvUpdateTypeMeta(dv, res)
+	return c.updateResource(ctx, namespace, name, res, dv, data)
 }
 
-func (c *client) DeleteDataVolume(namespace string, name string) error {
-	return c.deleteResource(namespace, name, dvRes())
+func (c *client) DeleteDataVolume(ctx context.Context, namespace string, name string) error {
+	res, err := c.dvRes(ctx)
+	if err != nil {
+		return err
+	}
+	return c.deleteResource(ctx, namespace, name, res)
 }
 
-func dvUpdateTypeMeta(dv *cdiv1.DataVolume) {
+func dvUpdateTypeMeta(dv *cdiv1.DataVolume, res schema.GroupVersionResource) {
 	dv.TypeMeta = metav1.TypeMeta{
 		Kind:       "DataVolume",
-		APIVersion: cdiv1.SchemeGroupVersion.String(),
+		APIVersion: res.GroupVersion().String(),
 	}
 }
 
-func dvRes() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    cdiv1.SchemeGroupVersion.Group,
-		Version:  cdiv1.SchemeGroupVersion.Version,
-		Resource: "datavolumes",
+func (c *client) dvRes(ctx context.Context) (schema.GroupVersionResource, error) {
+	return c.resource(ctx, CDI, "datavolumes")
+}
+
+// Secret CRUD operations
+
+var secretRes = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+func (c *client) CreateSecret(ctx context.Context, secret *corev1.Secret) error {
+	secretUpdateTypeMeta(secret)
+	return c.createResource(ctx, secret, secret.Namespace, secretRes)
+}
+
+func (c *client) GetSecret(ctx context.Context, namespace string, name string) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	resp, err := c.getResource(ctx, namespace, name, secretRes)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, err
+		}
+		msg := fmt.Sprintf("Failed to get Secret, with error: %v", err)
+		tflog.Error(ctx, msg)
+		return nil, fmt.Errorf(msg)
+	}
+	unstructured := resp.UnstructuredContent()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured, &secret); err != nil {
+		msg := fmt.Sprintf("Failed to translate unstructed to Secret, with error: %v", err)
+		tflog.Error(ctx, msg)
+		return nil, fmt.Errorf(msg)
+	}
+	return &secret, nil
+}
+
+func (c *client) UpdateSecret(ctx context.Context, namespace string, name string, secret *corev1.Secret, data []byte) error {
+	secretUpdateTypeMeta(secret)
+	return c.updateResource(ctx, namespace, name, secretRes, secret, data)
+}
+
+func (c *client) DeleteSecret(ctx context.Context, namespace string, name string) error {
+	return c.deleteResource(ctx, namespace, name, secretRes)
+}
+
+func secretUpdateTypeMeta(secret *corev1.Secret) {
+	secret.TypeMeta = metav1.TypeMeta{
+		Kind:       "Secret",
+		APIVersion: corev1.SchemeGroupVersion.String(),
 	}
 }
 
 // Generic Resource CRUD operations
 
-func (c *client) createResource(obj interface{}, namespace string, resource schema.GroupVersionResource) error {
+func (c *client) createResource(ctx context.Context, obj interface{}, namespace string, resource schema.GroupVersionResource) error {
 	resultMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
 		msg := fmt.Sprintf("Failed to translate %s to Unstructed (for create operation), with error: %v", resource.Resource, err)
-		log.Printf("[Error] %s", msg)
+		tflog.SubsystemError(ctx, utils.LogSubsystemClient, msg)
 		return fmt.Errorf(msg)
 	}
 	input := unstructured.Unstructured{}
 	input.SetUnstructuredContent(resultMap)
-	resp, err := c.dynamicClient.Resource(resource).Namespace(namespace).Create(context.Background(), &input, meta_v1.CreateOptions{})
+	resp, err := c.dynamicClient.Resource(resource).Namespace(namespace).Create(ctx, &input, meta_v1.CreateOptions{})
 	if err != nil {
 		msg := fmt.Sprintf("Failed to create %s, with error: %v", resource.Resource, err)
-		log.Printf("[Error] %s", msg)
+		tflog.SubsystemError(ctx, utils.LogSubsystemClient, msg)
 		return fmt.Errorf(msg)
 	}
 	unstructured := resp.UnstructuredContent()
 	return runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured, obj)
 }
 
-func (c *client) getResource(namespace string, name string, resource schema.GroupVersionResource) (*unstructured.Unstructured, error) {
-	return c.dynamicClient.Resource(resource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+func (c *client) getResource(ctx context.Context, namespace string, name string, resource schema.GroupVersionResource) (*unstructured.Unstructured, error) {
+	return c.dynamicClient.Resource(resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
-func (c *client) updateResource(namespace string, name string, resource schema.GroupVersionResource, obj interface{}, data []byte) error {
-	resp, err := c.dynamicClient.Resource(resource).Namespace(namespace).Patch(context.Background(), name, pkgApi.JSONPatchType, data, metav1.PatchOptions{})
+func (c *client) updateResource(ctx context.Context, namespace string, name string, resource schema.GroupVersionResource, obj interface{}, data []byte) error {
+	resp, err := c.dynamicClient.Resource(resource).Namespace(namespace).Patch(ctx, name, pkgApi.JSONPatchType, data, metav1.PatchOptions{})
 	if err != nil {
 		msg := fmt.Sprintf("Failed to update %s, with error: %v", resource.Resource, err)
-		log.Printf("[Error] %s", msg)
+		tflog.SubsystemError(ctx, utils.LogSubsystemClient, msg)
 		return fmt.Errorf(msg)
 	}
 	unstructured := resp.UnstructuredContent()
 	return runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured, obj)
 }
 
-func (c *client) deleteResource(namespace string, name string, resource schema.GroupVersionResource) error {
-	return c.dynamicClient.Resource(resource).Namespace(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+func (c *client) deleteResource(ctx context.Context, namespace string, name string, resource schema.GroupVersionResource) error {
+	return c.dynamicClient.Resource(resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
@@ -5,10 +5,13 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
-	v1 "kubevirt.io/api/core/v1"
+	client "github.com/kubevirt/terraform-provider-kubevirt/kubevirt/client"
+	v1 "k8s.io/api/core/v1"
+	v10 "kubevirt.io/api/core/v1"
 	v1beta1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
 
@@ -35,116 +38,188 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// Capabilities mocks base method.
+func (m *MockClient) Capabilities(ctx context.Context) (*client.Capabilities, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities", ctx)
+	ret0, _ := ret[0].(*client.Capabilities)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockClientMockRecorder) Capabilities(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockClient)(nil).Capabilities), ctx)
+}
+
 // CreateDataVolume mocks base method.
-func (m *MockClient) CreateDataVolume(vm *v1beta1.DataVolume) error {
+func (m *MockClient) CreateDataVolume(ctx context.Context, vm *v1beta1.DataVolume) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateDataVolume", vm)
+	ret := m.ctrl.Call(m, "CreateDataVolume", ctx, vm)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateDataVolume indicates an expected call of CreateDataVolume.
-func (mr *MockClientMockRecorder) CreateDataVolume(vm interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateDataVolume(ctx, vm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataVolume", reflect.TypeOf((*MockClient)(nil).CreateDataVolume), ctx, vm)
+}
+
+// CreateSecret mocks base method.
+func (m *MockClient) CreateSecret(ctx context.Context, secret *v1.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSecret", ctx, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSecret indicates an expected call of CreateSecret.
+func (mr *MockClientMockRecorder) CreateSecret(ctx, secret interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataVolume", reflect.TypeOf((*MockClient)(nil).CreateDataVolume), vm)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockClient)(nil).CreateSecret), ctx, secret)
 }
 
 // CreateVirtualMachine mocks base method.
-func (m *MockClient) CreateVirtualMachine(vm *v1.VirtualMachine) error {
+func (m *MockClient) CreateVirtualMachine(ctx context.Context, vm *v10.VirtualMachine) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVirtualMachine", vm)
+	ret := m.ctrl.Call(m, "CreateVirtualMachine", ctx, vm)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateVirtualMachine indicates an expected call of CreateVirtualMachine.
-func (mr *MockClientMockRecorder) CreateVirtualMachine(vm interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateVirtualMachine(ctx, vm interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVirtualMachine", reflect.TypeOf((*MockClient)(nil).CreateVirtualMachine), vm)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVirtualMachine", reflect.TypeOf((*MockClient)(nil).CreateVirtualMachine), ctx, vm)
 }
 
 // DeleteDataVolume mocks base method.
-func (m *MockClient) DeleteDataVolume(namespace, name string) error {
+func (m *MockClient) DeleteDataVolume(ctx context.Context, namespace, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteDataVolume", namespace, name)
+	ret := m.ctrl.Call(m, "DeleteDataVolume", ctx, namespace, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteDataVolume indicates an expected call of DeleteDataVolume.
-func (mr *MockClientMockRecorder) DeleteDataVolume(namespace, name interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteDataVolume(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataVolume", reflect.TypeOf((*MockClient)(nil).DeleteDataVolume), ctx, namespace, name)
+}
+
+// DeleteSecret mocks base method.
+func (m *MockClient) DeleteSecret(ctx context.Context, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecret", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecret indicates an expected call of DeleteSecret.
+func (mr *MockClientMockRecorder) DeleteSecret(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataVolume", reflect.TypeOf((*MockClient)(nil).DeleteDataVolume), namespace, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockClient)(nil).DeleteSecret), ctx, namespace, name)
 }
 
 // DeleteVirtualMachine mocks base method.
-func (m *MockClient) DeleteVirtualMachine(namespace, name string) error {
+func (m *MockClient) DeleteVirtualMachine(ctx context.Context, namespace, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteVirtualMachine", namespace, name)
+	ret := m.ctrl.Call(m, "DeleteVirtualMachine", ctx, namespace, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteVirtualMachine indicates an expected call of DeleteVirtualMachine.
-func (mr *MockClientMockRecorder) DeleteVirtualMachine(namespace, name interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) DeleteVirtualMachine(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVirtualMachine", reflect.TypeOf((*MockClient)(nil).DeleteVirtualMachine), namespace, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVirtualMachine", reflect.TypeOf((*MockClient)(nil).DeleteVirtualMachine), ctx, namespace, name)
 }
 
 // GetDataVolume mocks base method.
-func (m *MockClient) GetDataVolume(namespace, name string) (*v1beta1.DataVolume, error) {
+func (m *MockClient) GetDataVolume(ctx context.Context, namespace, name string) (*v1beta1.DataVolume, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetDataVolume", namespace, name)
+	ret := m.ctrl.Call(m, "GetDataVolume", ctx, namespace, name)
 	ret0, _ := ret[0].(*v1beta1.DataVolume)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetDataVolume indicates an expected call of GetDataVolume.
-func (mr *MockClientMockRecorder) GetDataVolume(namespace, name interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) GetDataVolume(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataVolume", reflect.TypeOf((*MockClient)(nil).GetDataVolume), ctx, namespace, name)
+}
+
+// GetSecret mocks base method.
+func (m *MockClient) GetSecret(ctx context.Context, namespace, name string) (*v1.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", ctx, namespace, name)
+	ret0, _ := ret[0].(*v1.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecret indicates an expected call of GetSecret.
+func (mr *MockClientMockRecorder) GetSecret(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataVolume", reflect.TypeOf((*MockClient)(nil).GetDataVolume), namespace, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockClient)(nil).GetSecret), ctx, namespace, name)
 }
 
 // GetVirtualMachine mocks base method.
-func (m *MockClient) GetVirtualMachine(namespace, name string) (*v1.VirtualMachine, error) {
+func (m *MockClient) GetVirtualMachine(ctx context.Context, namespace, name string) (*v10.VirtualMachine, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVirtualMachine", namespace, name)
-	ret0, _ := ret[0].(*v1.VirtualMachine)
+	ret := m.ctrl.Call(m, "GetVirtualMachine", ctx, namespace, name)
+	ret0, _ := ret[0].(*v10.VirtualMachine)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetVirtualMachine indicates an expected call of GetVirtualMachine.
-func (mr *MockClientMockRecorder) GetVirtualMachine(namespace, name interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) GetVirtualMachine(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVirtualMachine", reflect.TypeOf((*MockClient)(nil).GetVirtualMachine), namespace, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVirtualMachine", reflect.TypeOf((*MockClient)(nil).GetVirtualMachine), ctx, namespace, name)
 }
 
 // UpdateDataVolume mocks base method.
-func (m *MockClient) UpdateDataVolume(namespace, name string, dv *v1beta1.DataVolume, data []byte) error {
+func (m *MockClient) UpdateDataVolume(ctx context.Context, namespace, name string, dv *v1beta1.DataVolume, data []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDataVolume", namespace, name, dv, data)
+	ret := m.ctrl.Call(m, "UpdateDataVolume", ctx, namespace, name, dv, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateDataVolume indicates an expected call of UpdateDataVolume.
-func (mr *MockClientMockRecorder) UpdateDataVolume(namespace, name, dv, data interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) UpdateDataVolume(ctx, namespace, name, dv, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataVolume", reflect.TypeOf((*MockClient)(nil).UpdateDataVolume), ctx, namespace, name, dv, data)
+}
+
+// UpdateSecret mocks base method.
+func (m *MockClient) UpdateSecret(ctx context.Context, namespace, name string, secret *v1.Secret, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSecret", ctx, namespace, name, secret, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSecret indicates an expected call of UpdateSecret.
+func (mr *MockClientMockRecorder) UpdateSecret(ctx, namespace, name, secret, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataVolume", reflect.TypeOf((*MockClient)(nil).UpdateDataVolume), namespace, name, dv, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockClient)(nil).UpdateSecret), ctx, namespace, name, secret, data)
 }
 
 // UpdateVirtualMachine mocks base method.
-func (m *MockClient) UpdateVirtualMachine(namespace, name string, vm *v1.VirtualMachine, data []byte) error {
+func (m *MockClient) UpdateVirtualMachine(ctx context.Context, namespace, name string, vm *v10.VirtualMachine, data []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateVirtualMachine", namespace, name, vm, data)
+	ret := m.ctrl.Call(m, "UpdateVirtualMachine", ctx, namespace, name, vm, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateVirtualMachine indicates an expected call of UpdateVirtualMachine.
-func (mr *MockClientMockRecorder) UpdateVirtualMachine(namespace, name, vm, data interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) UpdateVirtualMachine(ctx, namespace, name, vm, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVirtualMachine", reflect.TypeOf((*MockClient)(nil).UpdateVirtualMachine), namespace, name, vm, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVirtualMachine", reflect.TypeOf((*MockClient)(nil).UpdateVirtualMachine), ctx, namespace, name, vm, data)
 }
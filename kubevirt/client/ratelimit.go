@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	"golang.org/x/time/rate"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// defaultRetryAfter is used when the server throttles us without a usable Retry-After header.
+	defaultRetryAfter = 1 * time.Second
+	// minQPSFactor bounds how far the limiter backs off, relative to the configured QPS.
+	minQPSFactor = 0.1
+	// recoveryFactor is the share of the configured QPS restored after every successful request.
+	recoveryFactor = 0.1
+)
+
+// throttleMetrics accumulates how long the requests of a single operation were held back on the
+// client side, either by the token bucket or because the server asked us to slow down. The
+// metrics travel in the context of the operation, so concurrent operations are counted apart.
+type throttleMetrics struct {
+	mu               sync.Mutex
+	requests         int64
+	rateLimitedTime  time.Duration
+	serverThrottled  int64
+	retryAfterWaited time.Duration
+}
+
+type throttleMetricsKey struct{}
+
+// WithThrottleMetrics returns a context collecting the throttling metrics of the requests made with it.
+func WithThrottleMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, throttleMetricsKey{}, &throttleMetrics{})
+}
+
+func throttleMetricsFrom(ctx context.Context) *throttleMetrics {
+	m, _ := ctx.Value(throttleMetricsKey{}).(*throttleMetrics)
+	return m
+}
+
+func (m *throttleMetrics) addWait(rateLimited, retryAfter time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests++
+	m.rateLimitedTime += rateLimited
+	m.retryAfterWaited += retryAfter
+}
+
+func (m *throttleMetrics) addServerThrottle() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.serverThrottled++
+}
+
+// LogThrottleMetrics writes the throttling metrics collected in a context returned by
+// WithThrottleMetrics to the debug log, as the metrics of the given operation.
+func LogThrottleMetrics(ctx context.Context, operation string) {
+	m := throttleMetricsFrom(ctx)
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ctx = tflog.NewSubsystem(ctx, utils.LogSubsystemClient, tflog.WithRootFields())
+	tflog.SubsystemDebug(ctx, utils.LogSubsystemClient, "Client throttling metrics of the operation", map[string]interface{}{
+		"operation":          operation,
+		"requests":           m.requests,
+		"rate_limited":       m.rateLimitedTime.String(),
+		"server_throttled":   m.serverThrottled,
+		"retry_after_waited": m.retryAfterWaited.String(),
+	})
+}
+
+// adaptiveRateLimiter is a token bucket rate limiter that halves its rate and pauses
+// all requests when the API server responds with 429 Too Many Requests, and then
+// gradually recovers to the configured QPS as requests succeed again.
+type adaptiveRateLimiter struct {
+	limiter *rate.Limiter
+	qps     float32
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+}
+
+var _ flowcontrol.RateLimiter = &adaptiveRateLimiter{}
+
+func newAdaptiveRateLimiter(qps float32, burst int) *adaptiveRateLimiter {
+	return &adaptiveRateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(qps), burst),
+		qps:     qps,
+	}
+}
+
+func (l *adaptiveRateLimiter) TryAccept() bool {
+	if l.pause() > 0 {
+		return false
+	}
+	return l.limiter.Allow()
+}
+
+func (l *adaptiveRateLimiter) Stop() {
+}
+
+func (l *adaptiveRateLimiter) QPS() float32 {
+	return float32(l.limiter.Limit())
+}
+
+func (l *adaptiveRateLimiter) Accept() {
+	_ = l.Wait(context.Background())
+}
+
+func (l *adaptiveRateLimiter) Wait(ctx context.Context) error {
+	var retryAfter time.Duration
+	if d := l.pause(); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		start := time.Now()
+		select {
+		case <-ctx.Done():
+			throttleMetricsFrom(ctx).addWait(0, time.Since(start))
+			return ctx.Err()
+		case <-timer.C:
+		}
+		retryAfter = time.Since(start)
+	}
+
+	start := time.Now()
+	err := l.limiter.Wait(ctx)
+	throttleMetricsFrom(ctx).addWait(time.Since(start), retryAfter)
+	return err
+}
+
+// pause returns how long requests still have to wait for the last Retry-After to expire.
+func (l *adaptiveRateLimiter) pause() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Until(l.blockedUntil)
+}
+
+func (l *adaptiveRateLimiter) backOff(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(retryAfter); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+
+	limit := l.limiter.Limit() / 2
+	if floor := rate.Limit(l.qps * minQPSFactor); limit < floor {
+		limit = floor
+	}
+	l.limiter.SetLimit(limit)
+}
+
+func (l *adaptiveRateLimiter) recover() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ceiling := rate.Limit(l.qps)
+	current := l.limiter.Limit()
+	if current >= ceiling {
+		return
+	}
+	limit := current + ceiling*recoveryFactor
+	if limit > ceiling {
+		limit = ceiling
+	}
+	l.limiter.SetLimit(limit)
+}
+
+// throttleRoundTripper reports the API server's throttling responses back to the rate limiter.
+type throttleRoundTripper struct {
+	next    http.RoundTripper
+	limiter *adaptiveRateLimiter
+}
+
+func (rt *throttleRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		throttleMetricsFrom(req.Context()).addServerThrottle()
+		wait := retryAfter(resp)
+		rt.limiter.backOff(wait)
+		tflog.SubsystemDebug(req.Context(), utils.LogSubsystemClient, "Throttled by the API server", map[string]interface{}{
+			"retry_after": wait.String(),
+			"qps":         rt.limiter.QPS(),
+		})
+	} else {
+		rt.limiter.recover()
+	}
+	return resp, nil
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return defaultRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return defaultRetryAfter
+}
+
+// withAdaptiveRateLimiter returns a copy of cfg whose requests go through an adaptive rate limiter
+// built from the configured QPS and burst.
+func withAdaptiveRateLimiter(cfg *restclient.Config) *restclient.Config {
+	result := restclient.CopyConfig(cfg)
+	if result.RateLimiter != nil {
+		return result
+	}
+
+	qps := result.QPS
+	if qps == 0.0 {
+		qps = restclient.DefaultQPS
+	}
+	burst := result.Burst
+	if burst == 0 {
+		burst = restclient.DefaultBurst
+	}
+
+	limiter := newAdaptiveRateLimiter(qps, burst)
+	result.RateLimiter = limiter
+	result.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttleRoundTripper{next: rt, limiter: limiter}
+	})
+	return result
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"golang.org/x/time/rate"
+)
+
+func TestRetryAfter(t *testing.T) {
+	testCases := []struct {
+		Header   string
+		Expected time.Duration
+	}{
+		{
+			Header:   "",
+			Expected: defaultRetryAfter,
+		},
+		{
+			Header:   "5",
+			Expected: 5 * time.Second,
+		},
+		{
+			Header:   "0",
+			Expected: defaultRetryAfter,
+		},
+		{
+			Header:   "not-a-date",
+			Expected: defaultRetryAfter,
+		},
+		{
+			Header:   "Mon, 02 Jan 2006 15:04:05 GMT",
+			Expected: defaultRetryAfter,
+		},
+	}
+
+	for _, tc := range testCases {
+		resp := &http.Response{Header: http.Header{}}
+		if tc.Header != "" {
+			resp.Header.Set("Retry-After", tc.Header)
+		}
+		if got := retryAfter(resp); got != tc.Expected {
+			t.Errorf("Retry-After %q: expected %s, got %s", tc.Header, tc.Expected, got)
+		}
+	}
+}
+
+func TestAdaptiveRateLimiterBackOffAndRecover(t *testing.T) {
+	limiter := newAdaptiveRateLimiter(20, 10)
+
+	limiter.backOff(time.Minute)
+	if got := limiter.limiter.Limit(); got != rate.Limit(10) {
+		t.Fatalf("expected QPS to be halved to 10, got %v", got)
+	}
+	if limiter.TryAccept() {
+		t.Fatalf("expected requests to be rejected while waiting for Retry-After")
+	}
+
+	for i := 0; i < 10; i++ {
+		limiter.backOff(0)
+	}
+	if got := limiter.limiter.Limit(); got != rate.Limit(2) {
+		t.Fatalf("expected QPS to be floored at 2, got %v", got)
+	}
+
+	for i := 0; i < 20; i++ {
+		limiter.recover()
+	}
+	if got := limiter.limiter.Limit(); got != rate.Limit(20) {
+		t.Fatalf("expected QPS to recover to 20, got %v", got)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLogThrottleMetrics(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	// Requests made outside of the operation are not counted.
+	limiter := newAdaptiveRateLimiter(1000, 10)
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = WithThrottleMetrics(ctx)
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rt := &throttleRoundTripper{
+		next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}, nil
+		}),
+		limiter: limiter,
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://cluster.example.com/apis", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	LogThrottleMetrics(ctx, "kubevirt_virtual_machine read")
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metrics map[string]interface{}
+	for _, entry := range entries {
+		if entry["@message"] == "Client throttling metrics of the operation" {
+			metrics = entry
+		}
+	}
+	if metrics == nil {
+		t.Fatalf("expected the throttling metrics to be logged, got %v", entries)
+	}
+	expected := map[string]interface{}{
+		"@module":          "provider.client",
+		"operation":        "kubevirt_virtual_machine read",
+		"requests":         float64(3),
+		"server_throttled": float64(1),
+	}
+	for key, value := range expected {
+		if metrics[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, metrics[key])
+		}
+	}
+}
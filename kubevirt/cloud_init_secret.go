@@ -0,0 +1,302 @@
+package kubevirt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/client"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/virtualmachineinstance"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils/patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+const (
+	// cloudInitInlineMaxLen is the size above which KubeVirt rejects inline cloud-init data.
+	cloudInitInlineMaxLen = 2048
+
+	cloudInitUserDataKey    = "userdata"
+	cloudInitNetworkDataKey = "networkdata"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "terraform-provider-kubevirt"
+
+	templateKey = "spec.0.template.0."
+)
+
+func cloudInitSecretName(vmName string) string {
+	return vmName + "-cloud-init"
+}
+
+func isManagedSecret(secret *corev1.Secret) bool {
+	return secret.Labels[managedByLabel] == managedByValue
+}
+
+// offloadCloudInitData moves the inline cloud-init payloads of the virtual machine into a Secret,
+// when they exceed what KubeVirt accepts inline or the volume is configured with store_in_secret,
+// and references the Secret instead. It returns the Secret to create, or nil if none is needed.
+func offloadCloudInitData(vm *kubevirtapiv1.VirtualMachine, resourceData utils.ResourceGetter) (*corev1.Secret, error) {
+	if vm.Spec.Template == nil {
+		return nil, nil
+	}
+	for i := range vm.Spec.Template.Spec.Volumes {
+		volume := &vm.Spec.Template.Spec.Volumes[i]
+		source, _ := virtualmachineinstance.CloudInitSource(volume)
+		if source == nil {
+			continue
+		}
+
+		userData, err := cloudInitPayload(source.UserData, source.UserDataBase64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user data of volume %s: %s", volume.Name, err)
+		}
+		networkData, err := cloudInitPayload(source.NetworkData, source.NetworkDataBase64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network data of volume %s: %s", volume.Name, err)
+		}
+		if len(userData) == 0 && len(networkData) == 0 {
+			continue
+		}
+		storeInSecret := virtualmachineinstance.CloudInitVolumeOption(resourceData, templateKey, volume.Name, virtualmachineinstance.CloudInitStoreInSecret)
+		if !storeInSecret && len(userData) <= cloudInitInlineMaxLen && len(networkData) <= cloudInitInlineMaxLen {
+			continue
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cloudInitSecretName(vm.Name),
+				Namespace: vm.Namespace,
+				Labels:    map[string]string{managedByLabel: managedByValue},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{},
+		}
+		ref := &corev1.LocalObjectReference{Name: secret.Name}
+		if len(userData) > 0 {
+			secret.Data[cloudInitUserDataKey] = userData
+			source.UserData = ""
+			source.UserDataBase64 = ""
+			source.UserDataSecretRef = ref
+		}
+		if len(networkData) > 0 {
+			secret.Data[cloudInitNetworkDataKey] = networkData
+			source.NetworkData = ""
+			source.NetworkDataBase64 = ""
+			source.NetworkDataSecretRef = ref
+		}
+		// KubeVirt allows a single cloud-init volume per virtual machine.
+		return secret, nil
+	}
+	return nil, nil
+}
+
+func cloudInitPayload(inline string, encoded string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if encoded != "" {
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+	return nil, nil
+}
+
+// restoreCloudInitData inlines back the payloads the provider offloaded to its Secret, so the
+// virtual machine matches its configuration.
+func restoreCloudInitData(ctx context.Context, cli client.Client, vm *kubevirtapiv1.VirtualMachine, resourceData utils.ResourceGetter) error {
+	if vm.Spec.Template == nil {
+		return nil
+	}
+	name := cloudInitSecretName(vm.Name)
+	for i := range vm.Spec.Template.Spec.Volumes {
+		volume := &vm.Spec.Template.Spec.Volumes[i]
+		source, _ := virtualmachineinstance.CloudInitSource(volume)
+		if source == nil {
+			continue
+		}
+		userDataOffloaded := source.UserDataSecretRef != nil && source.UserDataSecretRef.Name == name
+		networkDataOffloaded := source.NetworkDataSecretRef != nil && source.NetworkDataSecretRef.Name == name
+		if !userDataOffloaded && !networkDataOffloaded {
+			continue
+		}
+
+		secret, err := cli.GetSecret(ctx, vm.Namespace, name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				tflog.Warn(ctx, "Cloud-init secret not found", map[string]interface{}{"secret": name})
+				return nil
+			}
+			return err
+		}
+		if !isManagedSecret(secret) {
+			return nil
+		}
+
+		configured := virtualmachineinstance.CloudInitVolumeSource(resourceData, templateKey, volume.Name)
+		if userDataOffloaded {
+			source.UserDataSecretRef = nil
+			if encoded, _ := configured["user_data_base64"].(string); encoded != "" {
+				source.UserDataBase64 = base64.StdEncoding.EncodeToString(secret.Data[cloudInitUserDataKey])
+			} else {
+				source.UserData = string(secret.Data[cloudInitUserDataKey])
+			}
+		}
+		if networkDataOffloaded {
+			source.NetworkDataSecretRef = nil
+			if encoded, _ := configured["network_data_base64"].(string); encoded != "" {
+				source.NetworkDataBase64 = base64.StdEncoding.EncodeToString(secret.Data[cloudInitNetworkDataKey])
+			} else {
+				source.NetworkData = string(secret.Data[cloudInitNetworkDataKey])
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+func cloudInitSecretOwnerReferences(vm *kubevirtapiv1.VirtualMachine) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: kubevirtapiv1.GroupVersion.String(),
+			Kind:       "VirtualMachine",
+			Name:       vm.Name,
+			UID:        vm.UID,
+		},
+	}
+}
+
+// adoptCloudInitSecret makes the virtual machine the owner of its cloud-init Secret, so that the
+// Secret is garbage collected with it.
+func adoptCloudInitSecret(ctx context.Context, cli client.Client, vm *kubevirtapiv1.VirtualMachine) error {
+	ops := patch.PatchOperations{
+		&patch.AddOperation{
+			Path:  "/metadata/ownerReferences",
+			Value: cloudInitSecretOwnerReferences(vm),
+		},
+	}
+	data, err := ops.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("Failed to marshal update operations: %s", err)
+	}
+	return cli.UpdateSecret(ctx, vm.Namespace, cloudInitSecretName(vm.Name), &corev1.Secret{}, data)
+}
+
+// syncCloudInitSecret creates, updates or deletes the cloud-init Secret of the virtual machine
+// to match its configuration, and returns the operations patching the virtual machine's
+// cloud-init sources accordingly. Obsolete Secrets are only deleted once the virtual machine
+// no longer references them, so the caller deletes them after applying the operations.
+func syncCloudInitSecret(ctx context.Context, cli client.Client, vm *kubevirtapiv1.VirtualMachine, resourceData utils.ResourceGetter) (patch.PatchOperations, bool, error) {
+	secret, err := offloadCloudInitData(vm, resourceData)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name := cloudInitSecretName(vm.Name)
+	existing, err := cli.GetSecret(ctx, vm.Namespace, name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, false, err
+		}
+		existing = nil
+	}
+	if existing != nil && !isManagedSecret(existing) {
+		existing = nil
+	}
+
+	live, err := cli.GetVirtualMachine(ctx, vm.Namespace, vm.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	deleteSecret := false
+	switch {
+	case secret != nil && existing != nil:
+		ops := patch.PatchOperations{
+			&patch.ReplaceOperation{
+				Path:  "/data",
+				Value: secret.Data,
+			},
+		}
+		data, err := ops.MarshalJSON()
+		if err != nil {
+			return nil, false, fmt.Errorf("Failed to marshal update operations: %s", err)
+		}
+		tflog.Debug(ctx, "Updating cloud-init secret", map[string]interface{}{"secret": name})
+		if err := cli.UpdateSecret(ctx, vm.Namespace, name, &corev1.Secret{}, data); err != nil {
+			return nil, false, err
+		}
+	case secret != nil:
+		secret.OwnerReferences = cloudInitSecretOwnerReferences(live)
+		tflog.Debug(ctx, "Creating cloud-init secret", map[string]interface{}{"secret": name})
+		if err := cli.CreateSecret(ctx, secret); err != nil {
+			return nil, false, err
+		}
+	case existing != nil:
+		deleteSecret = true
+	}
+
+	var ops patch.PatchOperations
+	if vm.Spec.Template == nil {
+		return ops, deleteSecret, nil
+	}
+	for i := range vm.Spec.Template.Spec.Volumes {
+		volume := &vm.Spec.Template.Spec.Volumes[i]
+		source, field := virtualmachineinstance.CloudInitSource(volume)
+		if source == nil {
+			continue
+		}
+		// The configured volumes may be ordered differently from the live ones, so the patch
+		// targets the live volume by name, and fails if the volumes changed in the meantime.
+		index := liveVolumeIndex(live, volume.Name)
+		if index < 0 {
+			return nil, false, fmt.Errorf("cloud-init volume %s not found on the virtual machine", volume.Name)
+		}
+		ops = append(ops,
+			&patch.TestOperation{
+				Path:  fmt.Sprintf("/spec/template/spec/volumes/%d/name", index),
+				Value: volume.Name,
+			},
+			&patch.ReplaceOperation{
+				Path:  fmt.Sprintf("/spec/template/spec/volumes/%d/%s", index, field),
+				Value: source,
+			},
+		)
+	}
+	return ops, deleteSecret, nil
+}
+
+func liveVolumeIndex(vm *kubevirtapiv1.VirtualMachine, name string) int {
+	if vm.Spec.Template == nil {
+		return -1
+	}
+	for i, volume := range vm.Spec.Template.Spec.Volumes {
+		if volume.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// deleteCloudInitSecret deletes the cloud-init Secret of the virtual machine, if the provider created one.
+func deleteCloudInitSecret(ctx context.Context, cli client.Client, namespace string, vmName string) error {
+	name := cloudInitSecretName(vmName)
+	secret, err := cli.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !isManagedSecret(secret) {
+		return nil
+	}
+	tflog.Debug(ctx, "Deleting cloud-init secret", map[string]interface{}{"secret": name})
+	if err := cli.DeleteSecret(ctx, namespace, name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
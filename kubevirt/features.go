@@ -0,0 +1,320 @@
+package kubevirt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/client"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/datavolume"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/virtualmachine"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/virtualmachineinstance"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// virtualMachineFeatures lists the cluster features a virtual machine may depend on,
+// each with a check whether the virtual machine makes use of it.
+var virtualMachineFeatures = []struct {
+	feature client.Feature
+	used    func(vm *kubevirtapiv1.VirtualMachine) bool
+}{
+	{
+		feature: client.Feature{Name: "kubevirt_virtual_machine", Component: client.KubeVirt},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return true },
+	},
+	{
+		feature: client.Feature{Name: "data_volume_templates", Component: client.CDI},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return len(vm.Spec.DataVolumeTemplates) > 0 },
+	},
+	{
+		feature: client.Feature{Name: "data_volume_templates.spec.source.snapshot", Component: client.CDI, MinVersion: "1.56"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			for _, template := range vm.Spec.DataVolumeTemplates {
+				if usesSnapshotSource(template.Spec) {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		feature: client.Feature{Name: "instancetype", Component: client.KubeVirt, MinVersion: "0.56"},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return vm.Spec.Instancetype != nil },
+	},
+	{
+		feature: client.Feature{Name: "preference", Component: client.KubeVirt, MinVersion: "0.56"},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return vm.Spec.Preference != nil },
+	},
+	{
+		feature: client.Feature{Name: "dedicated_cpu_placement", Component: client.KubeVirt, FeatureGate: "CPUManager"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			cpu := vmDomain(vm).CPU
+			return cpu != nil && cpu.DedicatedCPUPlacement
+		},
+	},
+	{
+		feature: client.Feature{Name: "numa.guest_mapping_passthrough", Component: client.KubeVirt, FeatureGate: "NUMA"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			cpu := vmDomain(vm).CPU
+			return cpu != nil && cpu.NUMA != nil && cpu.NUMA.GuestMappingPassthrough != nil
+		},
+	},
+	{
+		feature: client.Feature{Name: "devices.gpus", Component: client.KubeVirt, FeatureGate: "GPU"},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return len(vmDomain(vm).Devices.GPUs) > 0 },
+	},
+	{
+		feature: client.Feature{Name: "devices.host_devices", Component: client.KubeVirt, FeatureGate: "HostDevices"},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return len(vmDomain(vm).Devices.HostDevices) > 0 },
+	},
+	{
+		feature: client.Feature{Name: "devices.filesystem", Component: client.KubeVirt, FeatureGate: "ExperimentalVirtiofsSupport"},
+		used:    func(vm *kubevirtapiv1.VirtualMachine) bool { return len(vmDomain(vm).Devices.Filesystems) > 0 },
+	},
+	{
+		feature: client.Feature{Name: "devices.autoattach_vsock", Component: client.KubeVirt, FeatureGate: "VSOCK"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			vsock := vmDomain(vm).Devices.AutoattachVSOCK
+			return vsock != nil && *vsock
+		},
+	},
+	{
+		feature: client.Feature{Name: "volume_source.persistent_volume_claim.hotpluggable", Component: client.KubeVirt, MinVersion: "0.38", FeatureGate: "HotplugVolumes"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			for _, volume := range vmVolumes(vm) {
+				if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.Hotpluggable {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		feature: client.Feature{Name: "volume_source.downward_metrics", Component: client.KubeVirt, FeatureGate: "DownwardMetrics"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			for _, volume := range vmVolumes(vm) {
+				if volume.DownwardMetrics != nil {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		feature: client.Feature{Name: "volume_source.host_disk", Component: client.KubeVirt, FeatureGate: "HostDisk"},
+		used: func(vm *kubevirtapiv1.VirtualMachine) bool {
+			for _, volume := range vmVolumes(vm) {
+				if volume.HostDisk != nil {
+					return true
+				}
+			}
+			return false
+		},
+	},
+}
+
+// virtualMachineValidators lists the plan-time checks of a virtual machine's template spec, each
+// with the keys of the values it checks. Values only known after apply expand to their zero
+// value, so a check is skipped unless all of them are known.
+var virtualMachineValidators = []struct {
+	keys     []string
+	validate func(spec kubevirtapiv1.VirtualMachineInstanceSpec) error
+}{
+	{
+		keys: []string{templateKey + "spec.0.domain.0.firmware", templateKey + "spec.0.domain.0.features"},
+		validate: func(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+			return virtualmachineinstance.ValidateFirmware(spec.Domain)
+		},
+	},
+	{
+		keys:     []string{templateKey + "spec.0.network", templateKey + "spec.0.domain.0.devices.0.interface"},
+		validate: virtualmachineinstance.ValidateNetworks,
+	},
+	{
+		keys:     []string{templateKey + "spec.0.domain.0.devices.0.interface"},
+		validate: virtualmachineinstance.ValidateInterfaces,
+	},
+	{
+		keys:     []string{templateKey + "spec.0.domain.0.devices.0.disk"},
+		validate: virtualmachineinstance.ValidateDisks,
+	},
+	{
+		keys:     []string{templateKey + "spec.0.volume"},
+		validate: virtualmachineinstance.ValidateCloudInit,
+	},
+	{
+		keys:     []string{templateKey + "spec.0.volume", templateKey + "spec.0.domain.0.devices.0.disk", templateKey + "spec.0.domain.0.devices.0.filesystem"},
+		validate: virtualmachineinstance.ValidateVolumes,
+	},
+	{
+		keys:     []string{templateKey + "spec.0.volume", templateKey + "spec.0.domain.0.devices.0.disk", templateKey + "spec.0.domain.0.devices.0.filesystem"},
+		validate: virtualmachineinstance.ValidateFilesystems,
+	},
+	{
+		keys:     []string{templateKey + "spec.0.access_credentials", templateKey + "spec.0.volume"},
+		validate: virtualmachineinstance.ValidateAccessCredentials,
+	},
+}
+
+// vmDomain returns the domain spec of the virtual machine's template, or an empty one.
+func vmDomain(vm *kubevirtapiv1.VirtualMachine) kubevirtapiv1.DomainSpec {
+	if vm.Spec.Template == nil {
+		return kubevirtapiv1.DomainSpec{}
+	}
+	return vm.Spec.Template.Spec.Domain
+}
+
+// vmVolumes returns the volumes of the virtual machine's template.
+func vmVolumes(vm *kubevirtapiv1.VirtualMachine) []kubevirtapiv1.Volume {
+	if vm.Spec.Template == nil {
+		return nil
+	}
+	return vm.Spec.Template.Spec.Volumes
+}
+
+// dataVolumeFeatures lists the cluster features a data volume may depend on,
+// each with a check whether the data volume makes use of it.
+var dataVolumeFeatures = []struct {
+	feature client.Feature
+	used    func(dv *cdiv1.DataVolume) bool
+}{
+	{
+		feature: client.Feature{Name: "kubevirt_data_volume", Component: client.CDI},
+		used:    func(dv *cdiv1.DataVolume) bool { return true },
+	},
+	{
+		feature: client.Feature{Name: "source.snapshot", Component: client.CDI, MinVersion: "1.56"},
+		used:    func(dv *cdiv1.DataVolume) bool { return usesSnapshotSource(dv.Spec) },
+	},
+}
+
+// usesSnapshotSource reports whether the data volume spec is populated from a VolumeSnapshot.
+func usesSnapshotSource(spec cdiv1.DataVolumeSpec) bool {
+	return spec.Source != nil && spec.Source.Snapshot != nil
+}
+
+func resourceKubevirtVirtualMachineCustomizeDiff(ctx context.Context, resourceDiff *schema.ResourceDiff, meta interface{}) error {
+	vm, err := virtualmachine.FromResourceData(resourceDiff)
+	if err != nil {
+		// Values only known after apply expand to their zero value, which may fail to expand.
+		if utils.ConfigKnown(resourceDiff, "metadata", "spec") {
+			return err
+		}
+		tflog.Debug(ctx, "Skipping plan-time checks, the virtual machine depends on values only known after apply", map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+
+	ctx = utils.NewLogContext(ctx, vm.Namespace, vm.Name)
+
+	if utils.ConfigKnown(resourceDiff, "spec.0.data_volume_templates") {
+		for _, template := range vm.Spec.DataVolumeTemplates {
+			if err := datavolume.ValidateDataVolumeSpec(template.Spec); err != nil {
+				return fmt.Errorf("data volume template %s: %s", template.Name, err)
+			}
+		}
+	}
+	if vm.Spec.Template != nil {
+		for _, v := range virtualMachineValidators {
+			if !utils.ConfigKnown(resourceDiff, v.keys...) {
+				tflog.Debug(ctx, "Skipping plan-time check, the checked values are only known after apply", map[string]interface{}{"keys": v.keys})
+				continue
+			}
+			if err := v.validate(vm.Spec.Template.Spec); err != nil {
+				return err
+			}
+		}
+		if utils.ConfigKnown(resourceDiff, templateKey+"spec.0.domain.0.memory", templateKey+"spec.0.domain.0.resources") {
+			warning, err := virtualmachineinstance.ValidateMemory(vm.Spec.Template.Spec.Domain)
+			if err != nil {
+				return err
+			}
+			if warning != "" {
+				// Plan-time diagnostics cannot be warnings, Create reports it to the user.
+				tflog.Warn(ctx, warning)
+			}
+		}
+	}
+
+	// Plan-time diagnostics cannot be warnings, Create and Update report them to the user.
+	_, err = checkClusterFeatures(ctx, meta, virtualMachineClusterFeatures(vm))
+	return err
+}
+
+func resourceKubevirtDataVolumeCustomizeDiff(ctx context.Context, resourceDiff *schema.ResourceDiff, meta interface{}) error {
+	dv, err := datavolume.FromResourceData(resourceDiff)
+	if err != nil {
+		if utils.ConfigKnown(resourceDiff, "metadata", "spec") {
+			return err
+		}
+		tflog.Debug(ctx, "Skipping plan-time checks, the data volume depends on values only known after apply", map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+
+	_, err = checkClusterFeatures(utils.NewLogContext(ctx, dv.Namespace, dv.Name), meta, dataVolumeClusterFeatures(dv))
+	return err
+}
+
+// virtualMachineClusterFeatures returns the cluster features the virtual machine makes use of.
+func virtualMachineClusterFeatures(vm *kubevirtapiv1.VirtualMachine) []client.Feature {
+	var features []client.Feature
+	for _, f := range virtualMachineFeatures {
+		if f.used(vm) {
+			features = append(features, f.feature)
+		}
+	}
+	return features
+}
+
+// dataVolumeClusterFeatures returns the cluster features the data volume makes use of.
+func dataVolumeClusterFeatures(dv *cdiv1.DataVolume) []client.Feature {
+	var features []client.Feature
+	for _, f := range dataVolumeFeatures {
+		if f.used(dv) {
+			features = append(features, f.feature)
+		}
+	}
+	return features
+}
+
+// checkClusterFeatures rejects features the target cluster is known not to support,
+// and returns warnings about the ones whose support could not be determined.
+func checkClusterFeatures(ctx context.Context, meta interface{}, features []client.Feature) ([]string, error) {
+	cli := (meta).(client.Client)
+
+	capabilities, err := cli.Capabilities(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Skipping cluster feature checks, unable to discover cluster capabilities", map[string]interface{}{"error": err.Error()})
+		return nil, nil
+	}
+
+	var warnings []string
+	for _, feature := range features {
+		warning, err := capabilities.Check(feature)
+		if err != nil {
+			return nil, err
+		}
+		if warning != "" {
+			tflog.Warn(ctx, warning)
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
+
+// clusterFeatureDiagnostics reports the cluster feature checks as diagnostics, so that the
+// warnings plan-time checks can only log reach the user when the resource is applied.
+func clusterFeatureDiagnostics(ctx context.Context, meta interface{}, features []client.Feature) diag.Diagnostics {
+	warnings, err := checkClusterFeatures(ctx, meta, features)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var diags diag.Diagnostics
+	for _, warning := range warnings {
+		diags = append(diags, diag.Diagnostic{Severity: diag.Warning, Summary: warning})
+	}
+	return diags
+}
@@ -2,12 +2,17 @@ package kubevirt
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/client"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
 	"github.com/mitchellh/go-homedir"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	restclient "k8s.io/client-go/rest"
@@ -100,31 +105,68 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("KUBE_LOAD_CONFIG_FILE", true),
 				Description: "Load local kubeconfig.",
 			},
+			"qps": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUBE_QPS", float64(restclient.DefaultQPS)),
+				ValidateFunc: validation.FloatAtLeast(0.1),
+				Description:  "The maximum number of queries per second sent to the Kubernetes API server. The rate is lowered automatically while the server answers with 429 Too Many Requests.",
+			},
+			"burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUBE_BURST", restclient.DefaultBurst),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum burst of queries sent to the Kubernetes API server on top of `qps`.",
+			},
+			"timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUBE_TIMEOUT", ""),
+				ValidateFunc: utils.ValidateDuration,
+				Description:  "The maximum time to wait for a single request to the Kubernetes API server, e.g. `30s`. No timeout is applied when unset.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"kubevirt_virtual_machine": resourceKubevirtVirtualMachine(),
 			"kubevirt_data_volume":     resourceKubevirtDataVolume(),
 		},
 	}
-	p.ConfigureFunc = func(resourceData *schema.ResourceData) (interface{}, error) {
+	p.ConfigureContextFunc = func(ctx context.Context, resourceData *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := p.TerraformVersion
 		if terraformVersion == "" {
 			// Terraform 0.12 introduced this field to the protocol
 			// We can therefore assume that if it's missing it's 0.10 or 0.11
 			terraformVersion = "0.11+compatible"
 		}
-		return providerConfigure(resourceData, terraformVersion)
+		cli, err := providerConfigure(ctx, resourceData, terraformVersion)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		return cli, nil
 	}
 	return p
 }
 
-func providerConfigure(resourceData *schema.ResourceData, terraformVersion string) (interface{}, error) {
+// withThrottleMetrics wraps a CRUD operation to log how much its own requests were throttled,
+// since the provider process has no hook running when Terraform is finished with it.
+func withThrottleMetrics(name string, operation func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx = client.WithThrottleMetrics(ctx)
+		defer func() {
+			client.LogThrottleMetrics(tflog.SetField(ctx, "id", resourceData.Id()), name)
+		}()
+		return operation(ctx, resourceData, meta)
+	}
+}
+
+func providerConfigure(ctx context.Context, resourceData *schema.ResourceData, terraformVersion string) (interface{}, error) {
 
 	var cfg *restclient.Config
 	var err error
 	if resourceData.Get("load_config_file").(bool) {
 		// Config file loading
-		cfg, err = tryLoadingConfigFile(resourceData)
+		cfg, err = tryLoadingConfigFile(ctx, resourceData)
 	}
 
 	if err != nil {
@@ -161,11 +203,24 @@ func providerConfigure(resourceData *schema.ResourceData, terraformVersion strin
 	if v, ok := resourceData.GetOk("token"); ok {
 		cfg.BearerToken = v.(string)
 	}
+	if v, ok := resourceData.GetOk("qps"); ok {
+		cfg.QPS = float32(v.(float64))
+	}
+	if v, ok := resourceData.GetOk("burst"); ok {
+		cfg.Burst = v.(int)
+	}
+	if v, ok := resourceData.GetOk("timeout"); ok {
+		timeout, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Timeout = timeout
+	}
 
-	return client.NewClient(cfg)
+	return client.NewClient(ctx, cfg)
 }
 
-func tryLoadingConfigFile(resourceData *schema.ResourceData) (*restclient.Config, error) {
+func tryLoadingConfigFile(ctx context.Context, resourceData *schema.ResourceData) (*restclient.Config, error) {
 	path, err := homedir.Expand(resourceData.Get("config_path").(string))
 	if err != nil {
 		return nil, err
@@ -178,15 +233,15 @@ func tryLoadingConfigFile(resourceData *schema.ResourceData) (*restclient.Config
 	overrides := &clientcmd.ConfigOverrides{}
 	ctxSuffix := "; default context"
 
-	ctx, ctxOk := resourceData.GetOk("config_context")
+	configContext, ctxOk := resourceData.GetOk("config_context")
 	authInfo, authInfoOk := resourceData.GetOk("config_context_auth_info")
 	cluster, clusterOk := resourceData.GetOk("config_context_cluster")
 	if ctxOk || authInfoOk || clusterOk {
 		ctxSuffix = "; overriden context"
 		if ctxOk {
-			overrides.CurrentContext = ctx.(string)
+			overrides.CurrentContext = configContext.(string)
 			ctxSuffix += fmt.Sprintf("; config ctx: %s", overrides.CurrentContext)
-			log.Printf("[DEBUG] Using custom current context: %q", overrides.CurrentContext)
+			tflog.Debug(ctx, "Using custom current context", map[string]interface{}{"context": overrides.CurrentContext})
 		}
 
 		overrides.Context = clientcmdapi.Context{}
@@ -198,19 +253,22 @@ func tryLoadingConfigFile(resourceData *schema.ResourceData) (*restclient.Config
 			overrides.Context.Cluster = cluster.(string)
 			ctxSuffix += fmt.Sprintf("; cluster: %s", overrides.Context.Cluster)
 		}
-		log.Printf("[DEBUG] Using overidden context: %#v", overrides.Context)
+		tflog.Debug(ctx, "Using overridden context", map[string]interface{}{
+			"auth_info": overrides.Context.AuthInfo,
+			"cluster":   overrides.Context.Cluster,
+		})
 	}
 
 	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
 	cfg, err := cc.ClientConfig()
 	if err != nil {
 		if pathErr, ok := err.(*os.PathError); ok && os.IsNotExist(pathErr.Err) {
-			log.Printf("[INFO] Unable to load config file as it doesn't exist at %q", path)
+			tflog.Info(ctx, "Unable to load config file as it doesn't exist", map[string]interface{}{"path": path})
 			return nil, nil
 		}
 		return nil, fmt.Errorf("Failed to load config (%s%s): %s", path, ctxSuffix, err)
 	}
 
-	log.Printf("[INFO] Successfully loaded config file (%s%s)", path, ctxSuffix)
+	tflog.Info(ctx, fmt.Sprintf("Successfully loaded config file (%s%s)", path, ctxSuffix))
 	return cfg, nil
 }
@@ -1,10 +1,12 @@
 package kubevirt
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/client"
@@ -17,13 +19,13 @@ import (
 
 func resourceKubevirtDataVolume() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceKubevirtDataVolumeCreate,
-		Read:   resourceKubevirtDataVolumeRead,
-		Update: resourceKubevirtDataVolumeUpdate,
-		Delete: resourceKubevirtDataVolumeDelete,
-		Exists: resourceKubevirtDataVolumeExists,
+		CreateContext: withThrottleMetrics("kubevirt_data_volume create", resourceKubevirtDataVolumeCreate),
+		ReadContext:   withThrottleMetrics("kubevirt_data_volume read", resourceKubevirtDataVolumeRead),
+		UpdateContext: withThrottleMetrics("kubevirt_data_volume update", resourceKubevirtDataVolumeUpdate),
+		DeleteContext: withThrottleMetrics("kubevirt_data_volume delete", resourceKubevirtDataVolumeDelete),
+		CustomizeDiff: resourceKubevirtDataVolumeCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(40 * time.Minute),
@@ -33,38 +35,44 @@ func resourceKubevirtDataVolume() *schema.Resource {
 	}
 }
 
-func resourceKubevirtDataVolumeCreate(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtDataVolumeCreate(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	dv, err := datavolume.FromResourceData(resourceData)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Creating new data volume: %#v", dv)
-	if err := cli.CreateDataVolume(dv); err != nil {
-		return err
+	name := dv.ObjectMeta.Name
+	namespace := dv.ObjectMeta.Namespace
+	ctx = utils.NewLogContext(ctx, namespace, name)
+
+	diags := clusterFeatureDiagnostics(ctx, meta, dataVolumeClusterFeatures(dv))
+	if diags.HasError() {
+		return diags
 	}
-	log.Printf("[INFO] Submitted new data volume: %#v", dv)
+
+	tflog.Info(ctx, "Creating new data volume", map[string]interface{}{"data_volume": utils.LogJSON(utils.RedactDataVolume(dv))})
+	if err := cli.CreateDataVolume(ctx, dv); err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Info(ctx, "Submitted new data volume", map[string]interface{}{"data_volume": utils.LogJSON(utils.RedactDataVolume(dv))})
 	if err := datavolume.ToResourceData(*dv, resourceData); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	resourceData.SetId(utils.BuildId(dv.ObjectMeta))
 
 	// Wait for data volume instance's status phase to be succeeded:
-	name := dv.ObjectMeta.Name
-	namespace := dv.ObjectMeta.Namespace
-
 	stateConf := &resource.StateChangeConf{
 		Pending: []string{"Creating"},
 		Target:  []string{"Succeeded"},
 		Timeout: resourceData.Timeout(schema.TimeoutCreate),
 		Refresh: func() (interface{}, string, error) {
 			var err error
-			dv, err = cli.GetDataVolume(namespace, name)
+			dv, err = cli.GetDataVolume(ctx, namespace, name)
 			if err != nil {
 				if errors.IsNotFound(err) {
-					log.Printf("[DEBUG] data volume %s is not created yet", name)
+					tflog.SubsystemDebug(ctx, utils.LogSubsystemWait, "Data volume is not created yet")
 					return dv, "Creating", nil
 				}
 				return dv, "", err
@@ -77,73 +85,93 @@ func resourceKubevirtDataVolumeCreate(resourceData *schema.ResourceData, meta in
 				return dv, "", fmt.Errorf("data volume failed to be created, finished with phase=\"failed\"")
 			}
 
-			log.Printf("[DEBUG] data volume %s is being created", name)
+			tflog.SubsystemDebug(ctx, utils.LogSubsystemWait, "Data volume is being created", map[string]interface{}{
+				"phase":    string(dv.Status.Phase),
+				"progress": string(dv.Status.Progress),
+			})
 			return dv, "Creating", nil
 		},
 	}
 
-	if _, err := stateConf.WaitForState(); err != nil {
-		return fmt.Errorf("%s", err)
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("%s", err)
 	}
-	return datavolume.ToResourceData(*dv, resourceData)
+	return append(diags, diag.FromErr(datavolume.ToResourceData(*dv, resourceData))...)
 }
 
-func resourceKubevirtDataVolumeRead(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtDataVolumeRead(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	namespace, name, err := utils.IdParts(resourceData.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
+	ctx = utils.NewLogContext(ctx, namespace, name)
 
-	log.Printf("[INFO] Reading data volume %s", name)
+	tflog.Info(ctx, "Reading data volume")
 
-	dv, err := cli.GetDataVolume(namespace, name)
+	dv, err := cli.GetDataVolume(ctx, namespace, name)
 	if err != nil {
-		log.Printf("[DEBUG] Received error: %#v", err)
-		return err
+		if errors.IsNotFound(err) {
+			tflog.Info(ctx, "Data volume not found, removing it from state")
+			resourceData.SetId("")
+			return nil
+		}
+		tflog.Debug(ctx, "Received error", map[string]interface{}{"error": err.Error()})
+		return diag.FromErr(err)
 	}
-	log.Printf("[INFO] Received data volume: %#v", dv)
+	tflog.Info(ctx, "Received data volume", map[string]interface{}{"data_volume": utils.LogJSON(utils.RedactDataVolume(dv))})
 
-	return datavolume.ToResourceData(*dv, resourceData)
+	return diag.FromErr(datavolume.ToResourceData(*dv, resourceData))
 }
 
-func resourceKubevirtDataVolumeUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtDataVolumeUpdate(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	namespace, name, err := utils.IdParts(resourceData.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	ctx = utils.NewLogContext(ctx, namespace, name)
+
+	dv, err := datavolume.FromResourceData(resourceData)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	diags := clusterFeatureDiagnostics(ctx, meta, dataVolumeClusterFeatures(dv))
+	if diags.HasError() {
+		return diags
 	}
 
 	ops := datavolume.AppendPatchOps("", "", resourceData, make([]patch.PatchOperation, 0, 0))
 	data, err := ops.MarshalJSON()
 	if err != nil {
-		return fmt.Errorf("Failed to marshal update operations: %s", err)
+		return diag.Errorf("Failed to marshal update operations: %s", err)
 	}
 
-	log.Printf("[INFO] Updating data volume: %s", ops)
+	tflog.SubsystemDebug(ctx, utils.LogSubsystemPatch, "Updating data volume", map[string]interface{}{"paths": ops.Paths()})
 	out := &cdiv1.DataVolume{}
-	if err := cli.UpdateDataVolume(namespace, name, out, data); err != nil {
-		return err
+	if err := cli.UpdateDataVolume(ctx, namespace, name, out, data); err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Submitted updated data volume: %#v", out)
+	tflog.Info(ctx, "Submitted updated data volume", map[string]interface{}{"data_volume": utils.LogJSON(utils.RedactDataVolume(out))})
 
-	return resourceKubevirtDataVolumeRead(resourceData, meta)
+	return append(diags, resourceKubevirtDataVolumeRead(ctx, resourceData, meta)...)
 }
 
-func resourceKubevirtDataVolumeDelete(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtDataVolumeDelete(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	namespace, name, err := utils.IdParts(resourceData.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
+	ctx = utils.NewLogContext(ctx, namespace, name)
 
-	log.Printf("[INFO] Deleting data volume: %#v", name)
-	if err := cli.DeleteDataVolume(namespace, name); err != nil {
-		return err
+	tflog.Info(ctx, "Deleting data volume")
+	if err := cli.DeleteDataVolume(ctx, namespace, name); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Wait for data volume instance to be removed:
@@ -151,7 +179,7 @@ func resourceKubevirtDataVolumeDelete(resourceData *schema.ResourceData, meta in
 		Pending: []string{"Deleting"},
 		Timeout: resourceData.Timeout(schema.TimeoutDelete),
 		Refresh: func() (interface{}, string, error) {
-			dv, err := cli.GetDataVolume(namespace, name)
+			dv, err := cli.GetDataVolume(ctx, namespace, name)
 			if err != nil {
 				if errors.IsNotFound(err) {
 					return nil, "", nil
@@ -159,36 +187,17 @@ func resourceKubevirtDataVolumeDelete(resourceData *schema.ResourceData, meta in
 				return dv, "", err
 			}
 
-			log.Printf("[DEBUG] data volume %s is being deleted", dv.GetName())
+			tflog.SubsystemDebug(ctx, utils.LogSubsystemWait, "Data volume is being deleted")
 			return dv, "Deleting", nil
 		},
 	}
 
-	if _, err := stateConf.WaitForState(); err != nil {
-		return fmt.Errorf("%s", err)
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("%s", err)
 	}
 
-	log.Printf("[INFO] data volume %s deleted", name)
+	tflog.Info(ctx, "Data volume deleted")
 
 	resourceData.SetId("")
 	return nil
 }
-
-func resourceKubevirtDataVolumeExists(resourceData *schema.ResourceData, meta interface{}) (bool, error) {
-	cli := (meta).(client.Client)
-
-	namespace, name, err := utils.IdParts(resourceData.Id())
-	if err != nil {
-		return false, err
-	}
-
-	log.Printf("[INFO] Checking data volume %s", name)
-	if _, err := cli.GetDataVolume(namespace, name); err != nil {
-		if statusErr, ok := err.(*errors.StatusError); ok && statusErr.ErrStatus.Code == 404 {
-			return false, nil
-		}
-		log.Printf("[DEBUG] Received error: %#v", err)
-		return true, err
-	}
-	return true, nil
-}
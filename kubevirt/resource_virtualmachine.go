@@ -1,14 +1,16 @@
 package kubevirt
 
 import (
-	"fmt"
-	"log"
+	"context"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/client"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/virtualmachine"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/virtualmachineinstance"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils/patch"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -17,13 +19,13 @@ import (
 
 func resourceKubevirtVirtualMachine() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceKubevirtVirtualMachineCreate,
-		Read:   resourceKubevirtVirtualMachineRead,
-		Update: resourceKubevirtVirtualMachineUpdate,
-		Delete: resourceKubevirtVirtualMachineDelete,
-		Exists: resourceKubevirtVirtualMachineExists,
+		CreateContext: withThrottleMetrics("kubevirt_virtual_machine create", resourceKubevirtVirtualMachineCreate),
+		ReadContext:   withThrottleMetrics("kubevirt_virtual_machine read", resourceKubevirtVirtualMachineRead),
+		UpdateContext: withThrottleMetrics("kubevirt_virtual_machine update", resourceKubevirtVirtualMachineUpdate),
+		DeleteContext: withThrottleMetrics("kubevirt_virtual_machine delete", resourceKubevirtVirtualMachineDelete),
+		CustomizeDiff: resourceKubevirtVirtualMachineCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(40 * time.Minute),
@@ -33,38 +35,71 @@ func resourceKubevirtVirtualMachine() *schema.Resource {
 	}
 }
 
-func resourceKubevirtVirtualMachineCreate(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtVirtualMachineCreate(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	vm, err := virtualmachine.FromResourceData(resourceData)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Creating new virtual machine: %#v", vm)
-	if err := cli.CreateVirtualMachine(vm); err != nil {
-		return err
+	name := vm.ObjectMeta.Name
+	namespace := vm.ObjectMeta.Namespace
+	ctx = utils.NewLogContext(ctx, namespace, name)
+
+	var diags diag.Diagnostics
+	if warning, _ := virtualmachineinstance.ValidateMemory(vmDomain(vm)); warning != "" {
+		diags = append(diags, diag.Diagnostic{Severity: diag.Warning, Summary: warning})
 	}
-	log.Printf("[INFO] Submitted new virtual machine: %#v", vm)
-	if err := virtualmachine.ToResourceData(*vm, resourceData); err != nil {
-		return err
+	if diags = append(diags, clusterFeatureDiagnostics(ctx, meta, virtualMachineClusterFeatures(vm))...); diags.HasError() {
+		return diags
+	}
+
+	secret, err := offloadCloudInitData(vm, resourceData)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if secret != nil {
+		tflog.Info(ctx, "Creating cloud-init secret", map[string]interface{}{"secret": secret.Name})
+		if err := cli.CreateSecret(ctx, secret); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	tflog.Info(ctx, "Creating new virtual machine", map[string]interface{}{"virtual_machine": utils.LogJSON(utils.RedactVirtualMachine(vm))})
+	if err := cli.CreateVirtualMachine(ctx, vm); err != nil {
+		if secret != nil {
+			if err := cli.DeleteSecret(ctx, secret.Namespace, secret.Name); err != nil {
+				tflog.Warn(ctx, "Failed to delete cloud-init secret", map[string]interface{}{"secret": secret.Name, "error": err.Error()})
+			}
+		}
+		return diag.FromErr(err)
 	}
 	resourceData.SetId(utils.BuildId(vm.ObjectMeta))
+	tflog.Info(ctx, "Submitted new virtual machine", map[string]interface{}{"virtual_machine": utils.LogJSON(utils.RedactVirtualMachine(vm))})
+	if secret != nil {
+		if err := adoptCloudInitSecret(ctx, cli, vm); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := restoreCloudInitData(ctx, cli, vm, resourceData); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := virtualmachine.ToResourceData(*vm, resourceData); err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Wait for virtual machine instance's status phase to be succeeded:
-	name := vm.ObjectMeta.Name
-	namespace := vm.ObjectMeta.Namespace
-
 	stateConf := &resource.StateChangeConf{
 		Pending: []string{"Creating"},
 		Target:  []string{"Succeeded"},
 		Timeout: resourceData.Timeout(schema.TimeoutCreate),
 		Refresh: func() (interface{}, string, error) {
 			var err error
-			vm, err = cli.GetVirtualMachine(namespace, name)
+			vm, err = cli.GetVirtualMachine(ctx, namespace, name)
 			if err != nil {
 				if errors.IsNotFound(err) {
-					log.Printf("[DEBUG] virtual machine %s is not created yet", name)
+					tflog.SubsystemDebug(ctx, utils.LogSubsystemWait, "Virtual machine is not created yet")
 					return vm, "Creating", nil
 				}
 				return vm, "", err
@@ -74,74 +109,118 @@ func resourceKubevirtVirtualMachineCreate(resourceData *schema.ResourceData, met
 				return vm, "Succeeded", nil
 			}
 
-			log.Printf("[DEBUG] virtual machine %s is being created", name)
+			tflog.SubsystemDebug(ctx, utils.LogSubsystemWait, "Virtual machine is being created", map[string]interface{}{
+				"created": vm.Status.Created,
+				"ready":   vm.Status.Ready,
+			})
 			return vm, "Creating", nil
 		},
 	}
 
-	if _, err := stateConf.WaitForState(); err != nil {
-		return fmt.Errorf("%s", err)
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("%s", err)
 	}
 
-	return resourceKubevirtVirtualMachineRead(resourceData, meta)
+	return append(diags, resourceKubevirtVirtualMachineRead(ctx, resourceData, meta)...)
 }
 
-func resourceKubevirtVirtualMachineRead(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtVirtualMachineRead(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	namespace, name, err := utils.IdParts(resourceData.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
+	ctx = utils.NewLogContext(ctx, namespace, name)
 
-	log.Printf("[INFO] Reading virtual machine %s", name)
+	tflog.Info(ctx, "Reading virtual machine")
 
-	vm, err := cli.GetVirtualMachine(namespace, name)
+	vm, err := cli.GetVirtualMachine(ctx, namespace, name)
 	if err != nil {
-		log.Printf("[DEBUG] Received error: %#v", err)
-		return err
+		if errors.IsNotFound(err) {
+			tflog.Info(ctx, "Virtual machine not found, removing it from state")
+			resourceData.SetId("")
+			return nil
+		}
+		tflog.Debug(ctx, "Received error", map[string]interface{}{"error": err.Error()})
+		return diag.FromErr(err)
 	}
-	log.Printf("[INFO] Received virtual machine: %#v", vm)
+	tflog.Info(ctx, "Received virtual machine", map[string]interface{}{"virtual_machine": utils.LogJSON(utils.RedactVirtualMachine(vm))})
 
-	return virtualmachine.ToResourceData(*vm, resourceData)
+	if err := restoreCloudInitData(ctx, cli, vm, resourceData); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(virtualmachine.ToResourceData(*vm, resourceData))
 }
 
-func resourceKubevirtVirtualMachineUpdate(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtVirtualMachineUpdate(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cli := (meta).(client.Client)
 
 	namespace, name, err := utils.IdParts(resourceData.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	ctx = utils.NewLogContext(ctx, namespace, name)
+
+	vm, err := virtualmachine.FromResourceData(resourceData)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	diags := clusterFeatureDiagnostics(ctx, meta, virtualMachineClusterFeatures(vm))
+	if diags.HasError() {
+		return diags
 	}
 
 	ops := virtualmachine.AppendPatchOps("", "", resourceData, make([]patch.PatchOperation, 0, 0))
+
+	deleteSecret := false
+	if resourceData.HasChange("spec.0.template.0.spec.0.volume") {
+		if vm.Spec.Template != nil {
+			virtualmachineinstance.ApplyCloudInitConfigData(&vm.Spec.Template.Spec, resourceData.GetRawConfig(), templateKey)
+		}
+		cloudInitOps, obsolete, err := syncCloudInitSecret(ctx, cli, vm, resourceData)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ops = append(ops, cloudInitOps...)
+		deleteSecret = obsolete
+	}
+
 	data, err := ops.MarshalJSON()
 	if err != nil {
-		return fmt.Errorf("Failed to marshal update operations: %s", err)
+		return diag.Errorf("Failed to marshal update operations: %s", err)
 	}
 
-	log.Printf("[INFO] Updating virtual machine: %s", ops)
+	tflog.SubsystemDebug(ctx, utils.LogSubsystemPatch, "Updating virtual machine", map[string]interface{}{"paths": ops.Paths()})
 	out := &kubevirtapiv1.VirtualMachine{}
-	if err := cli.UpdateVirtualMachine(namespace, name, out, data); err != nil {
-		return err
+	if err := cli.UpdateVirtualMachine(ctx, namespace, name, out, data); err != nil {
+		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Submitted updated virtual machine: %#v", out)
+	tflog.Info(ctx, "Submitted updated virtual machine", map[string]interface{}{"virtual_machine": utils.LogJSON(utils.RedactVirtualMachine(out))})
+
+	if deleteSecret {
+		if err := deleteCloudInitSecret(ctx, cli, namespace, name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-	return resourceKubevirtVirtualMachineRead(resourceData, meta)
+	return append(diags, resourceKubevirtVirtualMachineRead(ctx, resourceData, meta)...)
 }
 
-func resourceKubevirtVirtualMachineDelete(resourceData *schema.ResourceData, meta interface{}) error {
+func resourceKubevirtVirtualMachineDelete(ctx context.Context, resourceData *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	namespace, name, err := utils.IdParts(resourceData.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
+	ctx = utils.NewLogContext(ctx, namespace, name)
 
 	cli := (meta).(client.Client)
 
-	log.Printf("[INFO] Deleting virtual machine: %#v", name)
-	if err := cli.DeleteVirtualMachine(namespace, name); err != nil {
-		return err
+	tflog.Info(ctx, "Deleting virtual machine")
+	if err := cli.DeleteVirtualMachine(ctx, namespace, name); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Wait for virtual machine instance to be removed:
@@ -149,7 +228,7 @@ func resourceKubevirtVirtualMachineDelete(resourceData *schema.ResourceData, met
 		Pending: []string{"Deleting"},
 		Timeout: resourceData.Timeout(schema.TimeoutDelete),
 		Refresh: func() (interface{}, string, error) {
-			vm, err := cli.GetVirtualMachine(namespace, name)
+			vm, err := cli.GetVirtualMachine(ctx, namespace, name)
 			if err != nil {
 				if errors.IsNotFound(err) {
 					return nil, "", nil
@@ -157,36 +236,21 @@ func resourceKubevirtVirtualMachineDelete(resourceData *schema.ResourceData, met
 				return vm, "", err
 			}
 
-			log.Printf("[DEBUG] Virtual machine %s is being deleted", vm.GetName())
+			tflog.SubsystemDebug(ctx, utils.LogSubsystemWait, "Virtual machine is being deleted")
 			return vm, "Deleting", nil
 		},
 	}
 
-	if _, err := stateConf.WaitForState(); err != nil {
-		return fmt.Errorf("%s", err)
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("%s", err)
 	}
 
-	log.Printf("[INFO] virtual machine %s deleted", name)
+	tflog.Info(ctx, "Virtual machine deleted")
 
-	resourceData.SetId("")
-	return nil
-}
-
-func resourceKubevirtVirtualMachineExists(resourceData *schema.ResourceData, meta interface{}) (bool, error) {
-	namespace, name, err := utils.IdParts(resourceData.Id())
-	if err != nil {
-		return false, err
+	if err := deleteCloudInitSecret(ctx, cli, namespace, name); err != nil {
+		return diag.FromErr(err)
 	}
 
-	cli := (meta).(client.Client)
-
-	log.Printf("[INFO] Checking virtual machine %s", name)
-	if _, err := cli.GetVirtualMachine(namespace, name); err != nil {
-		if statusErr, ok := err.(*errors.StatusError); ok && statusErr.ErrStatus.Code == 404 {
-			return false, nil
-		}
-		log.Printf("[DEBUG] Received error: %#v", err)
-		return true, err
-	}
-	return true, nil
+	resourceData.SetId("")
+	return nil
 }
@@ -3,6 +3,7 @@ package datavolume
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/k8s"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils/patch"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
@@ -10,7 +11,7 @@ import (
 func DataVolumeFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"metadata": k8s.NamespacedMetadataSchema("DataVolume", false),
-		"spec":     DataVolumeSpecSchema(),
+		"spec":     DataVolumeSpecSchema("spec"),
 		"status":   dataVolumeStatusSchema(),
 	}
 }
@@ -57,7 +58,7 @@ func FlattenDataVolumeTemplates(in []cdiv1.DataVolume) []interface{} {
 	return att
 }
 
-func FromResourceData(resourceData *schema.ResourceData) (*cdiv1.DataVolume, error) {
+func FromResourceData(resourceData utils.ResourceGetter) (*cdiv1.DataVolume, error) {
 	result := &cdiv1.DataVolume{}
 
 	result.ObjectMeta = k8s.ExpandMetadata(resourceData.Get("metadata").([]interface{}))
@@ -7,6 +7,7 @@ import (
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/test_utils/flatten_utils"
 	"gotest.tools/assert"
 
+	k8sv1 "k8s.io/api/core/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -48,6 +49,36 @@ func TestExpandDataVolumeTemplates(t *testing.T) {
 			},
 			expectedErrorMessage: "quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
 		},
+		{
+			name:        "storage instead of pvc",
+			shouldError: false,
+			modifier: func(input interface{}) {
+				spec := input.(map[string]interface{})["spec"].([]interface{})[0].(map[string]interface{})
+				spec["storage"] = spec["pvc"]
+				delete(spec, "pvc")
+				dataSourceRef := spec["storage"].([]interface{})[0].(map[string]interface{})["data_source_ref"].([]interface{})[0]
+				delete(dataSourceRef.(map[string]interface{}), "namespace")
+			},
+			expectedOutput: []cdiv1.DataVolume{
+				withStorage(expand_utils.GetBaseOutputForDataVolume()),
+			},
+		},
+		{
+			name:        "storage without access modes and resources",
+			shouldError: false,
+			modifier: func(input interface{}) {
+				spec := input.(map[string]interface{})["spec"].([]interface{})[0].(map[string]interface{})
+				spec["storage"] = spec["pvc"]
+				delete(spec, "pvc")
+				storage := spec["storage"].([]interface{})[0].(map[string]interface{})
+				delete(storage, "access_modes")
+				delete(storage, "resources")
+				delete(storage["data_source_ref"].([]interface{})[0].(map[string]interface{}), "namespace")
+			},
+			expectedOutput: []cdiv1.DataVolume{
+				withoutStorageDefaults(withStorage(expand_utils.GetBaseOutputForDataVolume())),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -63,7 +94,7 @@ func TestExpandDataVolumeTemplates(t *testing.T) {
 				assert.Equal(t, tc.expectedErrorMessage, err.Error())
 			} else {
 				assert.NilError(t, err)
-				assert.DeepEqual(t, output[0], baseOutput)
+				assert.DeepEqual(t, output[0], tc.expectedOutput[0])
 			}
 		})
 	}
@@ -72,6 +103,12 @@ func TestExpandDataVolumeTemplates(t *testing.T) {
 func TestFlattenDataVolumeTemplates(t *testing.T) {
 	input1 := flatten_utils.GetBaseInputForDataVolume()
 	output1 := flatten_utils.GetBaseOutputForDataVolume()
+	input2 := withStorage(flatten_utils.GetBaseInputForDataVolume())
+	output2 := flatten_utils.GetBaseOutputForDataVolume()
+	spec2 := output2.(map[string]interface{})["spec"].([]interface{})[0].(map[string]interface{})
+	spec2["storage"] = spec2["pvc"]
+	delete(spec2, "pvc")
+	delete(spec2["storage"].([]interface{})[0].(map[string]interface{})["data_source_ref"].([]interface{})[0].(map[string]interface{}), "namespace")
 
 	cases := []struct {
 		Input          []cdiv1.DataVolume
@@ -85,6 +122,14 @@ func TestFlattenDataVolumeTemplates(t *testing.T) {
 				output1,
 			},
 		},
+		{
+			Input: []cdiv1.DataVolume{
+				input2,
+			},
+			ExpectedOutput: []interface{}{
+				output2,
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -100,7 +145,38 @@ func TestFlattenDataVolumeTemplates(t *testing.T) {
 }
 
 func nullifyUncomparableFields(output *[]interface{}) {
-	accessModes := (*output)[0].(map[string]interface{})["spec"].([]interface{})[0].(map[string]interface{})["pvc"].([]interface{})[0].(map[string]interface{})["access_modes"]
+	spec := (*output)[0].(map[string]interface{})["spec"].([]interface{})[0].(map[string]interface{})
+	for _, key := range []string{"pvc", "storage"} {
+		if v, ok := spec[key].([]interface{}); ok {
+			test_utils.NullifySchemaSetFunction(v[0].(map[string]interface{})["access_modes"].(*schema.Set))
+		}
+	}
+}
+
+// withStorage moves the PVC spec of the data volume to the storage spec.
+func withStorage(dataVolume cdiv1.DataVolume) cdiv1.DataVolume {
+	pvc := dataVolume.Spec.PVC
+	dataVolume.Spec.PVC = nil
+	dataVolume.Spec.Storage = &cdiv1.StorageSpec{
+		AccessModes:      pvc.AccessModes,
+		Selector:         pvc.Selector,
+		Resources:        pvc.Resources,
+		VolumeName:       pvc.VolumeName,
+		StorageClassName: pvc.StorageClassName,
+		VolumeMode:       pvc.VolumeMode,
+		DataSource:       pvc.DataSource,
+		DataSourceRef: &k8sv1.TypedLocalObjectReference{
+			APIGroup: pvc.DataSourceRef.APIGroup,
+			Kind:     pvc.DataSourceRef.Kind,
+			Name:     pvc.DataSourceRef.Name,
+		},
+	}
+	return dataVolume
+}
 
-	test_utils.NullifySchemaSetFunction(accessModes.(*schema.Set))
+// withoutStorageDefaults clears the storage spec fields CDI defaults from the storage profile.
+func withoutStorageDefaults(dataVolume cdiv1.DataVolume) cdiv1.DataVolume {
+	dataVolume.Spec.Storage.AccessModes = nil
+	dataVolume.Spec.Storage.Resources = k8sv1.ResourceRequirements{}
+	return dataVolume
 }
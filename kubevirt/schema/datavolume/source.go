@@ -4,18 +4,28 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
 
-func dataVolumeSourceFields() map[string]*schema.Schema {
+func dataVolumeSourceFields(key string) map[string]*schema.Schema {
+	registryKey := ""
+	if key != "" {
+		registryKey = key + ".0.registry"
+	}
+
 	return map[string]*schema.Schema{
-		"http": dataVolumeSourceHTTPSchema(),
-		"pvc":  dataVolumeSourcePVCSchema(),
+		"http":     dataVolumeSourceHTTPSchema(),
+		"pvc":      dataVolumeSourcePVCSchema(),
+		"registry": dataVolumeSourceRegistrySchema(registryKey),
+		"snapshot": dataVolumeSourceSnapshotSchema(),
 	}
 }
 
-func dataVolumeSourceSchema() *schema.Schema {
-	fields := dataVolumeSourceFields()
+// dataVolumeSourceSchema describes the source found under key, which is empty when the source
+// belongs to a list element that schema constraints cannot reference.
+func dataVolumeSourceSchema(key string) *schema.Schema {
+	fields := dataVolumeSourceFields(key)
 
 	return &schema.Schema{
 		Type:        schema.TypeList,
@@ -94,6 +104,92 @@ func dataVolumeSourcePVCSchema() *schema.Schema {
 
 }
 
+func dataVolumeSourceSnapshotFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"namespace": {
+			Type:        schema.TypeString,
+			Description: "The namespace of the source VolumeSnapshot.",
+			Required:    true,
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the source VolumeSnapshot.",
+			Required:    true,
+		},
+	}
+}
+
+func dataVolumeSourceSnapshotSchema() *schema.Schema {
+	fields := dataVolumeSourceSnapshotFields()
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "DataVolumeSourceSnapshot provides the parameters to create a Data Volume from an existing VolumeSnapshot.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+
+}
+
+func dataVolumeSourceRegistryFields(key string) map[string]*schema.Schema {
+	var images []string
+	if key != "" {
+		images = []string{key + ".0.url", key + ".0.image_stream"}
+	}
+
+	return map[string]*schema.Schema{
+		"url": {
+			Type:         schema.TypeString,
+			Description:  "URL is the url of the registry source, starting with the scheme: docker://, oci-archive://.",
+			Optional:     true,
+			ExactlyOneOf: images,
+		},
+		"image_stream": {
+			Type:         schema.TypeString,
+			Description:  "ImageStream is the name of image stream for import.",
+			Optional:     true,
+			ExactlyOneOf: images,
+		},
+		"pull_method": {
+			Type:        schema.TypeString,
+			Description: "PullMethod can be either \"pod\" (default import), or \"node\" (node docker cache based import).",
+			Optional:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(cdiv1.RegistryPullPod),
+				string(cdiv1.RegistryPullNode),
+			}, false),
+		},
+		"secret_ref": {
+			Type:        schema.TypeString,
+			Description: "SecretRef provides the secret reference needed to access the Registry source.",
+			Optional:    true,
+		},
+		"cert_config_map": {
+			Type:        schema.TypeString,
+			Description: "CertConfigMap provides a reference to the Registry certs.",
+			Optional:    true,
+		},
+	}
+}
+
+func dataVolumeSourceRegistrySchema(key string) *schema.Schema {
+	fields := dataVolumeSourceRegistryFields(key)
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "DataVolumeSourceRegistry provides the parameters to create a Data Volume from a registry source. Exactly one of url or image_stream must be set.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+
+}
+
 // Expanders
 
 func expandDataVolumeSource(dataVolumeSource []interface{}) *cdiv1.DataVolumeSource {
@@ -107,6 +203,12 @@ func expandDataVolumeSource(dataVolumeSource []interface{}) *cdiv1.DataVolumeSou
 
 	result.HTTP = expandDataVolumeSourceHTTP(in["http"].([]interface{}))
 	result.PVC = expandDataVolumeSourcePVC(in["pvc"].([]interface{}))
+	if v, ok := in["registry"].([]interface{}); ok {
+		result.Registry = expandDataVolumeSourceRegistry(v)
+	}
+	if v, ok := in["snapshot"].([]interface{}); ok {
+		result.Snapshot = expandDataVolumeSourceSnapshot(v)
+	}
 
 	return result
 }
@@ -152,6 +254,56 @@ func expandDataVolumeSourcePVC(dataVolumeSourcePVC []interface{}) *cdiv1.DataVol
 	return result
 }
 
+func expandDataVolumeSourceRegistry(dataVolumeSourceRegistry []interface{}) *cdiv1.DataVolumeSourceRegistry {
+	if len(dataVolumeSourceRegistry) == 0 {
+		return nil
+	}
+
+	result := &cdiv1.DataVolumeSourceRegistry{}
+
+	if dataVolumeSourceRegistry[0] != nil {
+		in := dataVolumeSourceRegistry[0].(map[string]interface{})
+
+		if v, ok := in["url"].(string); ok && v != "" {
+			result.URL = &v
+		}
+		if v, ok := in["image_stream"].(string); ok && v != "" {
+			result.ImageStream = &v
+		}
+		if v, ok := in["pull_method"].(string); ok && v != "" {
+			pullMethod := cdiv1.RegistryPullMethod(v)
+			result.PullMethod = &pullMethod
+		}
+		if v, ok := in["secret_ref"].(string); ok && v != "" {
+			result.SecretRef = &v
+		}
+		if v, ok := in["cert_config_map"].(string); ok && v != "" {
+			result.CertConfigMap = &v
+		}
+	}
+
+	return result
+}
+
+func expandDataVolumeSourceSnapshot(dataVolumeSourceSnapshot []interface{}) *cdiv1.DataVolumeSourceSnapshot {
+	if len(dataVolumeSourceSnapshot) == 0 || dataVolumeSourceSnapshot[0] == nil {
+		return nil
+	}
+
+	result := &cdiv1.DataVolumeSourceSnapshot{}
+
+	in := dataVolumeSourceSnapshot[0].(map[string]interface{})
+
+	if v, ok := in["namespace"].(string); ok {
+		result.Namespace = v
+	}
+	if v, ok := in["name"].(string); ok {
+		result.Name = v
+	}
+
+	return result
+}
+
 // Flatteners
 
 func flattenDataVolumeSource(in *cdiv1.DataVolumeSource) []interface{} {
@@ -163,6 +315,12 @@ func flattenDataVolumeSource(in *cdiv1.DataVolumeSource) []interface{} {
 	if in.PVC != nil {
 		att["pvc"] = flattenDataVolumeSourcePVC(*in.PVC)
 	}
+	if in.Registry != nil {
+		att["registry"] = flattenDataVolumeSourceRegistry(*in.Registry)
+	}
+	if in.Snapshot != nil {
+		att["snapshot"] = flattenDataVolumeSourceSnapshot(*in.Snapshot)
+	}
 
 	return []interface{}{att}
 }
@@ -183,3 +341,33 @@ func flattenDataVolumeSourcePVC(in cdiv1.DataVolumeSourcePVC) []interface{} {
 	}
 	return []interface{}{att}
 }
+
+func flattenDataVolumeSourceSnapshot(in cdiv1.DataVolumeSourceSnapshot) []interface{} {
+	att := map[string]interface{}{
+		"namespace": in.Namespace,
+		"name":      in.Name,
+	}
+	return []interface{}{att}
+}
+
+func flattenDataVolumeSourceRegistry(in cdiv1.DataVolumeSourceRegistry) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.URL != nil {
+		att["url"] = *in.URL
+	}
+	if in.ImageStream != nil {
+		att["image_stream"] = *in.ImageStream
+	}
+	if in.PullMethod != nil {
+		att["pull_method"] = string(*in.PullMethod)
+	}
+	if in.SecretRef != nil {
+		att["secret_ref"] = *in.SecretRef
+	}
+	if in.CertConfigMap != nil {
+		att["cert_config_map"] = *in.CertConfigMap
+	}
+
+	return []interface{}{att}
+}
@@ -9,10 +9,23 @@ import (
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 )
 
-func dataVolumeSpecFields() map[string]*schema.Schema {
+// dataVolumeSpecFields returns the fields of the spec found under key. The key is empty when the
+// spec is a list element, which schema constraints cannot reference, in which case
+// ValidateDataVolumeSpec checks them instead.
+func dataVolumeSpecFields(key string) map[string]*schema.Schema {
+	pvc := k8s.PersistentVolumeClaimSpecSchema()
+	storage := k8s.StorageSpecSchema()
+	sourceKey := ""
+	if key != "" {
+		pvc.ExactlyOneOf = []string{key + ".0.pvc", key + ".0.storage"}
+		storage.ExactlyOneOf = pvc.ExactlyOneOf
+		sourceKey = key + ".0.source"
+	}
+
 	return map[string]*schema.Schema{
-		"source": dataVolumeSourceSchema(),
-		"pvc":    k8s.PersistentVolumeClaimSpecSchema(),
+		"source":  dataVolumeSourceSchema(sourceKey),
+		"pvc":     pvc,
+		"storage": storage,
 		"content_type": {
 			Type:        schema.TypeString,
 			Description: "ContentType options: \"kubevirt\", \"archive\".",
@@ -25,12 +38,12 @@ func dataVolumeSpecFields() map[string]*schema.Schema {
 	}
 }
 
-func DataVolumeSpecSchema() *schema.Schema {
-	fields := dataVolumeSpecFields()
+func DataVolumeSpecSchema(key string) *schema.Schema {
+	fields := dataVolumeSpecFields(key)
 
 	return &schema.Schema{
 		Type:        schema.TypeList,
-		Description: fmt.Sprintf("DataVolumeSpec defines our specification for a DataVolume type"),
+		Description: fmt.Sprintf("DataVolumeSpec defines our specification for a DataVolume type. Exactly one of pvc or storage must be set."),
 		Required:    true,
 		MaxItems:    1,
 		Elem: &schema.Resource{
@@ -50,12 +63,20 @@ func ExpandDataVolumeSpec(dataVolumeSpec []interface{}) (cdiv1.DataVolumeSpec, e
 	in := dataVolumeSpec[0].(map[string]interface{})
 
 	result.Source = expandDataVolumeSource(in["source"].([]interface{}))
-	p, err := k8s.ExpandPersistentVolumeClaimSpec(in["pvc"].([]interface{}))
-	if err != nil {
-		return result, err
+	if v, ok := in["pvc"].([]interface{}); ok && len(v) > 0 {
+		p, err := k8s.ExpandPersistentVolumeClaimSpec(v)
+		if err != nil {
+			return result, err
+		}
+		result.PVC = p
+	}
+	if v, ok := in["storage"].([]interface{}); ok && len(v) > 0 {
+		storage, err := expandStorageSpec(v)
+		if err != nil {
+			return result, err
+		}
+		result.Storage = storage
 	}
-	result.PVC = p
-
 	if v, ok := in["content_type"].(string); ok {
 		result.ContentType = cdiv1.DataVolumeContentType(v)
 	}
@@ -63,11 +84,31 @@ func ExpandDataVolumeSpec(dataVolumeSpec []interface{}) (cdiv1.DataVolumeSpec, e
 	return result, nil
 }
 
+// ValidateDataVolumeSpec checks that exactly one of pvc or storage is set, and that a registry
+// source sets exactly one of url or image_stream, for the specs of data volume templates, whose
+// schema cannot declare it.
+func ValidateDataVolumeSpec(spec cdiv1.DataVolumeSpec) error {
+	if (spec.PVC == nil) == (spec.Storage == nil) {
+		return fmt.Errorf("exactly one of pvc or storage must be set in a data volume spec")
+	}
+	if spec.Source != nil && spec.Source.Registry != nil {
+		if registry := spec.Source.Registry; (registry.URL == nil) == (registry.ImageStream == nil) {
+			return fmt.Errorf("exactly one of url or image_stream must be set in a registry source")
+		}
+	}
+	return nil
+}
+
 func FlattenDataVolumeSpec(spec cdiv1.DataVolumeSpec) []interface{} {
 	att := map[string]interface{}{
 		"source":       flattenDataVolumeSource(spec.Source),
-		"pvc":          k8s.FlattenPersistentVolumeClaimSpec(*spec.PVC),
 		"content_type": string(spec.ContentType),
 	}
+	if spec.PVC != nil {
+		att["pvc"] = k8s.FlattenPersistentVolumeClaimSpec(*spec.PVC)
+	}
+	if spec.Storage != nil {
+		att["storage"] = flattenStorageSpec(*spec.Storage)
+	}
 	return []interface{}{att}
 }
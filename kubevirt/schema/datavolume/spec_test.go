@@ -0,0 +1,77 @@
+package datavolume
+
+import (
+	"testing"
+
+	k8sv1 "k8s.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateDataVolumeSpec(t *testing.T) {
+	url := "docker://registry.example.com/golden/fedora:38"
+	imageStream := "fedora"
+
+	cases := []struct {
+		name                 string
+		spec                 cdiv1.DataVolumeSpec
+		expectedErrorMessage string
+	}{
+		{
+			name: "pvc",
+			spec: cdiv1.DataVolumeSpec{PVC: &k8sv1.PersistentVolumeClaimSpec{}},
+		},
+		{
+			name: "storage",
+			spec: cdiv1.DataVolumeSpec{Storage: &cdiv1.StorageSpec{}},
+		},
+		{
+			name: "both pvc and storage",
+			spec: cdiv1.DataVolumeSpec{
+				PVC:     &k8sv1.PersistentVolumeClaimSpec{},
+				Storage: &cdiv1.StorageSpec{},
+			},
+			expectedErrorMessage: "exactly one of pvc or storage must be set in a data volume spec",
+		},
+		{
+			name: "registry image stream",
+			spec: cdiv1.DataVolumeSpec{
+				Source: &cdiv1.DataVolumeSource{Registry: &cdiv1.DataVolumeSourceRegistry{ImageStream: &imageStream}},
+				PVC:    &k8sv1.PersistentVolumeClaimSpec{},
+			},
+		},
+		{
+			name: "registry with both url and image stream",
+			spec: cdiv1.DataVolumeSpec{
+				Source: &cdiv1.DataVolumeSource{Registry: &cdiv1.DataVolumeSourceRegistry{URL: &url, ImageStream: &imageStream}},
+				PVC:    &k8sv1.PersistentVolumeClaimSpec{},
+			},
+			expectedErrorMessage: "exactly one of url or image_stream must be set in a registry source",
+		},
+		{
+			name: "registry without url or image stream",
+			spec: cdiv1.DataVolumeSpec{
+				Source:  &cdiv1.DataVolumeSource{Registry: &cdiv1.DataVolumeSourceRegistry{}},
+				Storage: &cdiv1.StorageSpec{},
+			},
+			expectedErrorMessage: "exactly one of url or image_stream must be set in a registry source",
+		},
+		{
+			name:                 "neither pvc nor storage",
+			spec:                 cdiv1.DataVolumeSpec{},
+			expectedErrorMessage: "exactly one of pvc or storage must be set in a data volume spec",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDataVolumeSpec(tc.spec)
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
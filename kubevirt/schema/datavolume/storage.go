@@ -0,0 +1,63 @@
+package datavolume
+
+import (
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/k8s"
+	k8sv1 "k8s.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// The storage block shares its fields with the pvc block, so both directions go through the
+// PersistentVolumeClaimSpec helpers of the k8s package.
+
+func expandStorageSpec(storage []interface{}) (*cdiv1.StorageSpec, error) {
+	if len(storage) == 0 || storage[0] == nil {
+		return nil, nil
+	}
+
+	spec, err := k8s.ExpandPersistentVolumeClaimSpec(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &cdiv1.StorageSpec{
+		Selector:         spec.Selector,
+		Resources:        spec.Resources,
+		VolumeName:       spec.VolumeName,
+		StorageClassName: spec.StorageClassName,
+		VolumeMode:       spec.VolumeMode,
+		DataSource:       spec.DataSource,
+	}
+	if len(spec.AccessModes) > 0 {
+		result.AccessModes = spec.AccessModes
+	}
+	if spec.DataSourceRef != nil {
+		result.DataSourceRef = &k8sv1.TypedLocalObjectReference{
+			APIGroup: spec.DataSourceRef.APIGroup,
+			Kind:     spec.DataSourceRef.Kind,
+			Name:     spec.DataSourceRef.Name,
+		}
+	}
+
+	return result, nil
+}
+
+func flattenStorageSpec(in cdiv1.StorageSpec) []interface{} {
+	spec := k8sv1.PersistentVolumeClaimSpec{
+		AccessModes:      in.AccessModes,
+		Selector:         in.Selector,
+		Resources:        in.Resources,
+		VolumeName:       in.VolumeName,
+		StorageClassName: in.StorageClassName,
+		VolumeMode:       in.VolumeMode,
+		DataSource:       in.DataSource,
+	}
+	if in.DataSourceRef != nil {
+		spec.DataSourceRef = &k8sv1.TypedObjectReference{
+			APIGroup: in.DataSourceRef.APIGroup,
+			Kind:     in.DataSourceRef.Kind,
+			Name:     in.DataSourceRef.Name,
+		}
+	}
+
+	return k8s.FlattenPersistentVolumeClaimSpec(spec)
+}
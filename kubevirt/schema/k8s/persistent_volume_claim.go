@@ -71,7 +71,71 @@ func persistentVolumeClaimSpecFields() map[string]*schema.Schema {
 			Computed:    true,
 			ForceNew:    true,
 		},
+		"volume_mode": {
+			Type:        schema.TypeString,
+			Description: "Defines what type of volume is required by the claim, Block or Filesystem. Defaults to Filesystem.",
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(v1.PersistentVolumeBlock),
+				string(v1.PersistentVolumeFilesystem),
+			}, false),
+		},
+		"data_source": {
+			Type:        schema.TypeList,
+			Description: "An existing VolumeSnapshot or PersistentVolumeClaim to populate the volume from.",
+			Optional:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: typedObjectReferenceFields(false),
+			},
+		},
+		"data_source_ref": {
+			Type:        schema.TypeList,
+			Description: "The object to populate the volume from. Unlike data_source it may reference any object from a non-empty API group, and a namespace when the CrossNamespaceVolumeDataSource feature gate is enabled.",
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: typedObjectReferenceFields(true),
+			},
+		},
+	}
+}
+
+func typedObjectReferenceFields(namespaced bool) map[string]*schema.Schema {
+	fields := map[string]*schema.Schema{
+		"api_group": {
+			Type:        schema.TypeString,
+			Description: "The group for the resource being referenced. If not specified, kind must be in the core API group.",
+			Optional:    true,
+			ForceNew:    true,
+		},
+		"kind": {
+			Type:        schema.TypeString,
+			Description: "The type of resource being referenced.",
+			Required:    true,
+			ForceNew:    true,
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of resource being referenced.",
+			Required:    true,
+			ForceNew:    true,
+		},
+	}
+	if namespaced {
+		fields["namespace"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "The namespace of resource being referenced. Defaults to the namespace of the claim.",
+			Optional:    true,
+			ForceNew:    true,
+		}
 	}
+	return fields
 }
 
 func PersistentVolumeClaimSpecSchema() *schema.Schema {
@@ -80,7 +144,7 @@ func PersistentVolumeClaimSpecSchema() *schema.Schema {
 	return &schema.Schema{
 		Type:        schema.TypeList,
 		Description: "PVC is a pointer to the PVC Spec we want to use.",
-		Required:    true,
+		Optional:    true,
 		MaxItems:    1,
 		Elem: &schema.Resource{
 			Schema: fields,
@@ -89,6 +153,33 @@ func PersistentVolumeClaimSpecSchema() *schema.Schema {
 
 }
 
+// StorageSpecSchema describes a PVC spec whose unset values are filled in by the cluster, as
+// done by CDI from the StorageProfile of the storage class.
+func StorageSpecSchema() *schema.Schema {
+	fields := persistentVolumeClaimSpecFields()
+
+	fields["access_modes"].Required = false
+	fields["access_modes"].Optional = true
+	fields["access_modes"].Computed = true
+	fields["resources"].Required = false
+	fields["resources"].Optional = true
+	fields["resources"].Computed = true
+	fields["data_source_ref"].Description = "The object to populate the volume from. Unlike data_source it may reference any object from a non-empty API group."
+	fields["data_source_ref"].Elem = &schema.Resource{
+		Schema: typedObjectReferenceFields(false),
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Storage is the requested storage of the DataVolume. Access modes, volume mode and the size overhead of the filesystem default to the StorageProfile of the storage class when not set.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
 // Flatteners
 
 func FlattenPersistentVolumeClaimSpec(in v1.PersistentVolumeClaimSpec) []interface{} {
@@ -104,6 +195,29 @@ func FlattenPersistentVolumeClaimSpec(in v1.PersistentVolumeClaimSpec) []interfa
 	if in.StorageClassName != nil {
 		att["storage_class_name"] = *in.StorageClassName
 	}
+	if in.VolumeMode != nil {
+		att["volume_mode"] = string(*in.VolumeMode)
+	}
+	if in.DataSource != nil {
+		att["data_source"] = flattenTypedObjectReference(in.DataSource.APIGroup, in.DataSource.Kind, in.DataSource.Name)
+	}
+	if in.DataSourceRef != nil {
+		dataSourceRef := flattenTypedObjectReference(in.DataSourceRef.APIGroup, in.DataSourceRef.Kind, in.DataSourceRef.Name)
+		if in.DataSourceRef.Namespace != nil {
+			dataSourceRef[0].(map[string]interface{})["namespace"] = *in.DataSourceRef.Namespace
+		}
+		att["data_source_ref"] = dataSourceRef
+	}
+	return []interface{}{att}
+}
+
+func flattenTypedObjectReference(apiGroup *string, kind string, name string) []interface{} {
+	att := make(map[string]interface{})
+	if apiGroup != nil {
+		att["api_group"] = *apiGroup
+	}
+	att["kind"] = kind
+	att["name"] = name
 	return []interface{}{att}
 }
 
@@ -126,12 +240,17 @@ func ExpandPersistentVolumeClaimSpec(l []interface{}) (*v1.PersistentVolumeClaim
 		return obj, nil
 	}
 	in := l[0].(map[string]interface{})
-	resourceRequirements, err := expandResourceRequirements(in["resources"].([]interface{}))
-	if err != nil {
-		return nil, err
+	// Both are optional in a storage spec, where CDI defaults them from the storage profile.
+	if v, ok := in["resources"].([]interface{}); ok {
+		resourceRequirements, err := expandResourceRequirements(v)
+		if err != nil {
+			return nil, err
+		}
+		obj.Resources = *resourceRequirements
+	}
+	if v, ok := in["access_modes"].(*schema.Set); ok {
+		obj.AccessModes = expandPersistentVolumeAccessModes(v.List())
 	}
-	obj.AccessModes = expandPersistentVolumeAccessModes(in["access_modes"].(*schema.Set).List())
-	obj.Resources = *resourceRequirements
 	if v, ok := in["selector"].([]interface{}); ok && len(v) > 0 {
 		obj.Selector = expandLabelSelector(v)
 	}
@@ -141,9 +260,39 @@ func ExpandPersistentVolumeClaimSpec(l []interface{}) (*v1.PersistentVolumeClaim
 	if v, ok := in["storage_class_name"].(string); ok && v != "" {
 		obj.StorageClassName = utils.PtrToString(v)
 	}
+	if v, ok := in["volume_mode"].(string); ok && v != "" {
+		volumeMode := v1.PersistentVolumeMode(v)
+		obj.VolumeMode = &volumeMode
+	}
+	if v, ok := in["data_source"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		ref := v[0].(map[string]interface{})
+		obj.DataSource = &v1.TypedLocalObjectReference{
+			APIGroup: expandAPIGroup(ref),
+			Kind:     ref["kind"].(string),
+			Name:     ref["name"].(string),
+		}
+	}
+	if v, ok := in["data_source_ref"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		ref := v[0].(map[string]interface{})
+		obj.DataSourceRef = &v1.TypedObjectReference{
+			APIGroup: expandAPIGroup(ref),
+			Kind:     ref["kind"].(string),
+			Name:     ref["name"].(string),
+		}
+		if namespace, ok := ref["namespace"].(string); ok && namespace != "" {
+			obj.DataSourceRef.Namespace = utils.PtrToString(namespace)
+		}
+	}
 	return obj, nil
 }
 
+func expandAPIGroup(ref map[string]interface{}) *string {
+	if v, ok := ref["api_group"].(string); ok && v != "" {
+		return utils.PtrToString(v)
+	}
+	return nil
+}
+
 func expandResourceRequirements(l []interface{}) (*v1.ResourceRequirements, error) {
 	obj := &v1.ResourceRequirements{}
 	if len(l) == 0 || l[0] == nil {
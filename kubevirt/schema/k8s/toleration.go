@@ -2,7 +2,6 @@ package k8s
 
 import (
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
@@ -97,8 +96,8 @@ func FlattenTolerations(tolerations []v1.Toleration) []interface{} {
 	att := []interface{}{}
 	for _, v := range tolerations {
 		// The API Server may automatically add several Tolerations to pods, strip these to avoid TF diff.
+		// They are still visible in the logged virtual machine.
 		if strings.Contains(v.Key, "node.kubernetes.io/") {
-			log.Printf("[INFO] ignoring toleration with key: %s", v.Key)
 			continue
 		}
 		obj := map[string]interface{}{}
@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	v1 "k8s.io/api/core/v1"
+)
+
+func topologySpreadConstraintFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"max_skew": {
+			Type:         schema.TypeInt,
+			Description:  "Describes the degree to which pods may be unevenly distributed. It's the maximum permitted difference between the number of matching pods in any two topology domains of a given topology type.",
+			Required:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"topology_key": {
+			Type:        schema.TypeString,
+			Description: "TopologyKey is the key of node labels. Nodes that have a label with this key and identical values are considered to be in the same topology.",
+			Required:    true,
+		},
+		"when_unsatisfiable": {
+			Type:         schema.TypeString,
+			Description:  "WhenUnsatisfiable indicates how to deal with a pod if it doesn't satisfy the spread constraint. Valid values are DoNotSchedule and ScheduleAnyway.",
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{string(v1.DoNotSchedule), string(v1.ScheduleAnyway)}, false),
+		},
+		"label_selector": {
+			Type:        schema.TypeList,
+			Description: "LabelSelector is used to find matching pods. Pods that match this label selector are counted to determine the number of pods in their corresponding topology domain.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: labelSelectorFields(true),
+			},
+		},
+	}
+}
+
+func TopologySpreadConstraintSchema() *schema.Schema {
+	fields := topologySpreadConstraintFields()
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "TopologySpreadConstraints describes how a group of VMIs will be spread across a given topology domains. K8s scheduler will schedule VMI pods in a way which abides by the constraints.",
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
+func ExpandTopologySpreadConstraints(constraints []interface{}) []v1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+	obj := make([]v1.TopologySpreadConstraint, len(constraints))
+	for i, c := range constraints {
+		in := c.(map[string]interface{})
+
+		obj[i].MaxSkew = int32(in["max_skew"].(int))
+		obj[i].TopologyKey = in["topology_key"].(string)
+		obj[i].WhenUnsatisfiable = v1.UnsatisfiableConstraintAction(in["when_unsatisfiable"].(string))
+		if v, ok := in["label_selector"].([]interface{}); ok && len(v) > 0 {
+			obj[i].LabelSelector = expandLabelSelector(v)
+		}
+	}
+	return obj
+}
+
+func FlattenTopologySpreadConstraints(constraints []v1.TopologySpreadConstraint) []interface{} {
+	att := make([]interface{}, len(constraints))
+	for i, c := range constraints {
+		m := make(map[string]interface{})
+		m["max_skew"] = int(c.MaxSkew)
+		m["topology_key"] = c.TopologyKey
+		m["when_unsatisfiable"] = string(c.WhenUnsatisfiable)
+		if c.LabelSelector != nil {
+			m["label_selector"] = flattenLabelSelector(c.LabelSelector)
+		}
+		att[i] = m
+	}
+	return att
+}
@@ -12,7 +12,7 @@ import (
 func DataVolumeFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"metadata": k8s.NamespacedMetadataSchema("DataVolume", false),
-		"spec":     datavolume.DataVolumeSpecSchema(),
+		"spec":     datavolume.DataVolumeSpecSchema(""),
 	}
 }
 
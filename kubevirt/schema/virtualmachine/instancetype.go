@@ -0,0 +1,113 @@
+package virtualmachine
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func matcherFields(resource string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Name is the name of the VirtualMachine%s or VirtualMachineCluster%s.", resource, resource),
+			Required:    true,
+		},
+		"kind": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Kind specifies which %s resource is referenced. Allowed values are: \"VirtualMachine%s\" and \"VirtualMachineCluster%s\". Defaults to \"VirtualMachineCluster%s\".", resource, resource, resource, resource),
+			Optional:    true,
+		},
+		"revision_name": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("RevisionName specifies a ControllerRevision containing a specific copy of the %s to be used. It is captured by KubeVirt the first time the %s is applied.", resource, resource),
+			Optional:    true,
+			Computed:    true,
+		},
+	}
+}
+
+func instancetypeMatcherSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "InstancetypeMatcher references an instancetype that is used to fill fields in the template.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: matcherFields("Instancetype"),
+		},
+	}
+}
+
+func preferenceMatcherSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "PreferenceMatcher references a preference that is used to fill fields in the template.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: matcherFields("Preference"),
+		},
+	}
+}
+
+func expandInstancetypeMatcher(matcher []interface{}) *kubevirtapiv1.InstancetypeMatcher {
+	if len(matcher) == 0 || matcher[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.InstancetypeMatcher{}
+	in := matcher[0].(map[string]interface{})
+
+	if v, ok := in["name"].(string); ok {
+		result.Name = v
+	}
+	if v, ok := in["kind"].(string); ok {
+		result.Kind = v
+	}
+	if v, ok := in["revision_name"].(string); ok {
+		result.RevisionName = v
+	}
+
+	return result
+}
+
+func expandPreferenceMatcher(matcher []interface{}) *kubevirtapiv1.PreferenceMatcher {
+	if len(matcher) == 0 || matcher[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.PreferenceMatcher{}
+	in := matcher[0].(map[string]interface{})
+
+	if v, ok := in["name"].(string); ok {
+		result.Name = v
+	}
+	if v, ok := in["kind"].(string); ok {
+		result.Kind = v
+	}
+	if v, ok := in["revision_name"].(string); ok {
+		result.RevisionName = v
+	}
+
+	return result
+}
+
+func flattenInstancetypeMatcher(in kubevirtapiv1.InstancetypeMatcher) []interface{} {
+	att := map[string]interface{}{
+		"name":          in.Name,
+		"kind":          in.Kind,
+		"revision_name": in.RevisionName,
+	}
+	return []interface{}{att}
+}
+
+func flattenPreferenceMatcher(in kubevirtapiv1.PreferenceMatcher) []interface{} {
+	att := map[string]interface{}{
+		"name":          in.Name,
+		"kind":          in.Kind,
+		"revision_name": in.RevisionName,
+	}
+	return []interface{}{att}
+}
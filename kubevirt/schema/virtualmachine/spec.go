@@ -28,6 +28,8 @@ func virtualMachineSpecFields() map[string]*schema.Schema {
 				"RerunOnFailure",
 			}, false),
 		},
+		"instancetype":          instancetypeMatcherSchema(),
+		"preference":            preferenceMatcherSchema(),
 		"template":              virtualmachineinstance.VirtualMachineInstanceTemplateSpecSchema(),
 		"data_volume_templates": dataVolumeTemplatesSchema(),
 	}
@@ -67,6 +69,12 @@ func expandVirtualMachineSpec(virtualMachine []interface{}) (kubevirtapiv1.Virtu
 			result.RunStrategy = &runStrategy
 		}
 	}
+	if v, ok := in["instancetype"].([]interface{}); ok {
+		result.Instancetype = expandInstancetypeMatcher(v)
+	}
+	if v, ok := in["preference"].([]interface{}); ok {
+		result.Preference = expandPreferenceMatcher(v)
+	}
 	if v, ok := in["template"].([]interface{}); ok {
 		template, err := virtualmachineinstance.ExpandVirtualMachineInstanceTemplateSpec(v)
 		if err != nil {
@@ -94,6 +102,12 @@ func flattenVirtualMachineSpec(in kubevirtapiv1.VirtualMachineSpec) []interface{
 	if in.RunStrategy != nil {
 		att["run_strategy"] = string(*in.RunStrategy)
 	}
+	if in.Instancetype != nil {
+		att["instancetype"] = flattenInstancetypeMatcher(*in.Instancetype)
+	}
+	if in.Preference != nil {
+		att["preference"] = flattenPreferenceMatcher(*in.Preference)
+	}
 	if in.Template != nil {
 		att["template"] = virtualmachineinstance.FlattenVirtualMachineInstanceTemplateSpec(*in.Template)
 	}
@@ -3,6 +3,8 @@ package virtualmachine
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/k8s"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/virtualmachineinstance"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils/patch"
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
 )
@@ -55,7 +57,7 @@ func FlattenVirtualMachine(in kubevirtapiv1.VirtualMachine) []interface{} {
 	return []interface{}{att}
 }
 
-func FromResourceData(resourceData *schema.ResourceData) (*kubevirtapiv1.VirtualMachine, error) {
+func FromResourceData(resourceData utils.ResourceGetter) (*kubevirtapiv1.VirtualMachine, error) {
 	result := &kubevirtapiv1.VirtualMachine{}
 
 	result.ObjectMeta = k8s.ExpandMetadata(resourceData.Get("metadata").([]interface{}))
@@ -77,7 +79,11 @@ func ToResourceData(vm kubevirtapiv1.VirtualMachine, resourceData *schema.Resour
 	if err := resourceData.Set("metadata", k8s.FlattenMetadata(vm.ObjectMeta)); err != nil {
 		return err
 	}
-	if err := resourceData.Set("spec", flattenVirtualMachineSpec(vm.Spec)); err != nil {
+	spec := flattenVirtualMachineSpec(vm.Spec)
+	if template, ok := spec[0].(map[string]interface{})["template"].([]interface{}); ok {
+		virtualmachineinstance.ApplyCloudInitStateOptions(template, resourceData, "spec.0.template.0.")
+	}
+	if err := resourceData.Set("spec", spec); err != nil {
 		return err
 	}
 	if err := resourceData.Set("status", flattenVirtualMachineStatus(vm.Status)); err != nil {
@@ -167,3 +167,48 @@ func nullifyUncomparableFields(output *[]interface{}) {
 	nodePreferredMatchFields := nodePreference["match_fields"].([]interface{})[0].(map[string]interface{})["values"]
 	test_utils.NullifySchemaSetFunction(nodePreferredMatchFields.(*schema.Set))
 }
+
+func TestToResourceDataHashCloudInitData(t *testing.T) {
+	volumeKey := "spec.0.template.0.spec.0.volume.0.volume_source.0.cloud_init_config_drive.0."
+	raw := map[string]interface{}{
+		"metadata": []interface{}{
+			map[string]interface{}{"name": "test-vm", "namespace": "default"},
+		},
+		"spec": []interface{}{
+			map[string]interface{}{
+				"template": []interface{}{
+					map[string]interface{}{
+						"spec": []interface{}{
+							map[string]interface{}{
+								"volume": []interface{}{
+									map[string]interface{}{
+										"name": "cloudinit",
+										"volume_source": []interface{}{
+											map[string]interface{}{
+												"cloud_init_config_drive": []interface{}{
+													map[string]interface{}{
+														"user_data":     "#cloud-config\npassword: secret",
+														"hash_in_state": true,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	resourceData := schema.TestResourceDataRaw(t, VirtualMachineFields(), raw)
+
+	vm, err := FromResourceData(resourceData)
+	assert.NilError(t, err)
+	assert.NilError(t, ToResourceData(*vm, resourceData))
+
+	assert.Equal(t, resourceData.Get(volumeKey+"user_data"), "sha256:046fc08315933b95bb9389fd8c9d1a246cc4406258ca71f04a5f5cb97a2ffcf1")
+	assert.Equal(t, resourceData.Get(volumeKey+"hash_in_state"), true)
+	assert.Equal(t, resourceData.Get(volumeKey+"network_data"), "")
+}
@@ -0,0 +1,291 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func accessCredentialsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Specifies a set of public keys and passwords to inject into the vmi. Credentials propagated through the qemu guest agent are updated in the running guest when their Secret changes.",
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ssh_public_key": {
+					Type:        schema.TypeList,
+					Description: "SSHPublicKey represents the source and method of applying a ssh public key into a guest virtual machine. Exactly one of ssh_public_key or user_password must be set.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"source": accessCredentialSourceSchema("SSH public keys"),
+							"propagation_method": {
+								Type:        schema.TypeList,
+								Description: "PropagationMethod represents how the public key is injected into the vm guest. Exactly one of config_drive or qemu_guest_agent must be set.",
+								MaxItems:    1,
+								Required:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"config_drive": {
+											Type:        schema.TypeList,
+											Description: "ConfigDrive propagates the keys through the cloud_init_config_drive volume at first boot. Changes to the keys are not applied to a running guest.",
+											MaxItems:    1,
+											Optional:    true,
+											Elem: &schema.Resource{
+												Schema: map[string]*schema.Schema{},
+											},
+										},
+										"qemu_guest_agent": {
+											Type:        schema.TypeList,
+											Description: "QemuGuestAgent propagates the keys to the authorized_keys file of the users, and keeps them in sync with the Secret. Requires the qemu guest agent to be installed in the guest.",
+											MaxItems:    1,
+											Optional:    true,
+											Elem: &schema.Resource{
+												Schema: map[string]*schema.Schema{
+													"users": {
+														Type:        schema.TypeList,
+														Description: "Users represents a list of guest users that should have the ssh public keys added to their authorized_keys file.",
+														Required:    true,
+														MinItems:    1,
+														Elem: &schema.Schema{
+															Type: schema.TypeString,
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"user_password": {
+					Type:        schema.TypeList,
+					Description: "UserPassword represents the source and method for applying a guest user's password. Exactly one of ssh_public_key or user_password must be set.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"source": accessCredentialSourceSchema("user names as keys and passwords as values"),
+							"propagation_method": {
+								Type:        schema.TypeList,
+								Description: "PropagationMethod represents how the user passwords are injected into the vm guest.",
+								MaxItems:    1,
+								Required:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"qemu_guest_agent": {
+											Type:        schema.TypeList,
+											Description: "QemuGuestAgent sets the passwords of existing guest users, and keeps them in sync with the Secret. Requires the qemu guest agent to be installed in the guest.",
+											MaxItems:    1,
+											Required:    true,
+											Elem: &schema.Resource{
+												Schema: map[string]*schema.Schema{},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func accessCredentialSourceSchema(content string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Source represents where the credentials are stored.",
+		MaxItems:    1,
+		Required:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"secret": {
+					Type:        schema.TypeList,
+					Description: fmt.Sprintf("Secret means that the access credential is pulled from a kubernetes secret, holding %s.", content),
+					MaxItems:    1,
+					Required:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"secret_name": {
+								Type:        schema.TypeString,
+								Description: "SecretName represents the name of the secret in the vmi's namespace.",
+								Required:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandAccessCredentials(accessCredentials []interface{}) ([]kubevirtapiv1.AccessCredential, error) {
+	result := make([]kubevirtapiv1.AccessCredential, len(accessCredentials))
+
+	for i, accessCredential := range accessCredentials {
+		if accessCredential == nil {
+			return result, fmt.Errorf("exactly one of ssh_public_key or user_password must be set in access_credentials")
+		}
+		in := accessCredential.(map[string]interface{})
+
+		if v, ok := in["ssh_public_key"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+			sshPublicKey, err := expandSSHPublicKeyAccessCredential(v[0].(map[string]interface{}))
+			if err != nil {
+				return result, err
+			}
+			result[i].SSHPublicKey = sshPublicKey
+		}
+		if v, ok := in["user_password"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+			result[i].UserPassword = expandUserPasswordAccessCredential(v[0].(map[string]interface{}))
+		}
+		if (result[i].SSHPublicKey == nil) == (result[i].UserPassword == nil) {
+			return result, fmt.Errorf("exactly one of ssh_public_key or user_password must be set in access_credentials")
+		}
+	}
+
+	return result, nil
+}
+
+func expandSSHPublicKeyAccessCredential(in map[string]interface{}) (*kubevirtapiv1.SSHPublicKeyAccessCredential, error) {
+	result := &kubevirtapiv1.SSHPublicKeyAccessCredential{}
+
+	if v, ok := in["source"].([]interface{}); ok {
+		result.Source.Secret = expandAccessCredentialSecretSource(v)
+	}
+	if v, ok := in["propagation_method"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		method := v[0].(map[string]interface{})
+		if configDrive, ok := method["config_drive"].([]interface{}); ok && len(configDrive) > 0 {
+			result.PropagationMethod.ConfigDrive = &kubevirtapiv1.ConfigDriveSSHPublicKeyAccessCredentialPropagation{}
+		}
+		if agent, ok := method["qemu_guest_agent"].([]interface{}); ok && len(agent) > 0 && agent[0] != nil {
+			result.PropagationMethod.QemuGuestAgent = &kubevirtapiv1.QemuGuestAgentSSHPublicKeyAccessCredentialPropagation{}
+			for _, user := range agent[0].(map[string]interface{})["users"].([]interface{}) {
+				result.PropagationMethod.QemuGuestAgent.Users = append(result.PropagationMethod.QemuGuestAgent.Users, user.(string))
+			}
+		}
+	}
+	if (result.PropagationMethod.ConfigDrive == nil) == (result.PropagationMethod.QemuGuestAgent == nil) {
+		return result, fmt.Errorf("exactly one of config_drive or qemu_guest_agent must be set in the ssh_public_key propagation_method")
+	}
+
+	return result, nil
+}
+
+func expandUserPasswordAccessCredential(in map[string]interface{}) *kubevirtapiv1.UserPasswordAccessCredential {
+	result := &kubevirtapiv1.UserPasswordAccessCredential{}
+
+	if v, ok := in["source"].([]interface{}); ok {
+		result.Source.Secret = expandAccessCredentialSecretSource(v)
+	}
+	if v, ok := in["propagation_method"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		if agent, ok := v[0].(map[string]interface{})["qemu_guest_agent"].([]interface{}); ok && len(agent) > 0 {
+			result.PropagationMethod.QemuGuestAgent = &kubevirtapiv1.QemuGuestAgentUserPasswordAccessCredentialPropagation{}
+		}
+	}
+
+	return result
+}
+
+func expandAccessCredentialSecretSource(source []interface{}) *kubevirtapiv1.AccessCredentialSecretSource {
+	if len(source) == 0 || source[0] == nil {
+		return nil
+	}
+	secret, ok := source[0].(map[string]interface{})["secret"].([]interface{})
+	if !ok || len(secret) == 0 || secret[0] == nil {
+		return nil
+	}
+
+	return &kubevirtapiv1.AccessCredentialSecretSource{
+		SecretName: secret[0].(map[string]interface{})["secret_name"].(string),
+	}
+}
+
+func flattenAccessCredentials(in []kubevirtapiv1.AccessCredential) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		if v.SSHPublicKey != nil {
+			c["ssh_public_key"] = flattenSSHPublicKeyAccessCredential(*v.SSHPublicKey)
+		}
+		if v.UserPassword != nil {
+			c["user_password"] = flattenUserPasswordAccessCredential(*v.UserPassword)
+		}
+
+		att[i] = c
+	}
+
+	return att
+}
+
+func flattenSSHPublicKeyAccessCredential(in kubevirtapiv1.SSHPublicKeyAccessCredential) []interface{} {
+	att := make(map[string]interface{})
+
+	att["source"] = flattenAccessCredentialSecretSource(in.Source.Secret)
+	method := make(map[string]interface{})
+	if in.PropagationMethod.ConfigDrive != nil {
+		method["config_drive"] = []interface{}{map[string]interface{}{}}
+	}
+	if in.PropagationMethod.QemuGuestAgent != nil {
+		users := make([]interface{}, len(in.PropagationMethod.QemuGuestAgent.Users))
+		for i, user := range in.PropagationMethod.QemuGuestAgent.Users {
+			users[i] = user
+		}
+		method["qemu_guest_agent"] = []interface{}{map[string]interface{}{
+			"users": users,
+		}}
+	}
+	att["propagation_method"] = []interface{}{method}
+
+	return []interface{}{att}
+}
+
+func flattenUserPasswordAccessCredential(in kubevirtapiv1.UserPasswordAccessCredential) []interface{} {
+	att := make(map[string]interface{})
+
+	att["source"] = flattenAccessCredentialSecretSource(in.Source.Secret)
+	method := make(map[string]interface{})
+	if in.PropagationMethod.QemuGuestAgent != nil {
+		method["qemu_guest_agent"] = []interface{}{map[string]interface{}{}}
+	}
+	att["propagation_method"] = []interface{}{method}
+
+	return []interface{}{att}
+}
+
+func flattenAccessCredentialSecretSource(in *kubevirtapiv1.AccessCredentialSecretSource) []interface{} {
+	source := make(map[string]interface{})
+
+	if in != nil {
+		source["secret"] = []interface{}{map[string]interface{}{
+			"secret_name": in.SecretName,
+		}}
+	}
+
+	return []interface{}{source}
+}
+
+// ValidateAccessCredentials checks that ssh public keys propagated through the config drive have
+// a cloud-init config drive volume to be written to.
+func ValidateAccessCredentials(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+	for _, credential := range spec.AccessCredentials {
+		if credential.SSHPublicKey == nil || credential.SSHPublicKey.PropagationMethod.ConfigDrive == nil {
+			continue
+		}
+		for _, volume := range spec.Volumes {
+			if volume.CloudInitConfigDrive != nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("ssh_public_key propagated through config_drive requires a cloud_init_config_drive volume")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestExpandAccessCredentials(t *testing.T) {
+	source := []interface{}{map[string]interface{}{
+		"secret": []interface{}{map[string]interface{}{"secret_name": "ssh-keys"}},
+	}}
+	sshPublicKey := func(method map[string]interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"source":             source,
+			"propagation_method": []interface{}{method},
+		}}
+	}
+
+	cases := []struct {
+		name                 string
+		credential           map[string]interface{}
+		expectedErrorMessage string
+	}{
+		{
+			name: "ssh public key through the config drive",
+			credential: map[string]interface{}{
+				"ssh_public_key": sshPublicKey(map[string]interface{}{"config_drive": []interface{}{nil}}),
+			},
+		},
+		{
+			name:                 "no credential",
+			credential:           map[string]interface{}{},
+			expectedErrorMessage: "exactly one of ssh_public_key or user_password must be set in access_credentials",
+		},
+		{
+			name: "two propagation methods",
+			credential: map[string]interface{}{
+				"ssh_public_key": sshPublicKey(map[string]interface{}{
+					"config_drive":     []interface{}{nil},
+					"qemu_guest_agent": []interface{}{map[string]interface{}{"users": []interface{}{"fedora"}}},
+				}),
+			},
+			expectedErrorMessage: "exactly one of config_drive or qemu_guest_agent must be set in the ssh_public_key propagation_method",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := expandAccessCredentials([]interface{}{tc.credential})
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestValidateAccessCredentials(t *testing.T) {
+	spec := kubevirtapiv1.VirtualMachineInstanceSpec{
+		AccessCredentials: []kubevirtapiv1.AccessCredential{{
+			SSHPublicKey: &kubevirtapiv1.SSHPublicKeyAccessCredential{
+				PropagationMethod: kubevirtapiv1.SSHPublicKeyAccessCredentialPropagationMethod{
+					ConfigDrive: &kubevirtapiv1.ConfigDriveSSHPublicKeyAccessCredentialPropagation{},
+				},
+			},
+		}},
+	}
+	assert.Error(t, ValidateAccessCredentials(spec), "ssh_public_key propagated through config_drive requires a cloud_init_config_drive volume")
+
+	spec.Volumes = []kubevirtapiv1.Volume{{
+		Name: "cloudinit",
+		VolumeSource: kubevirtapiv1.VolumeSource{
+			CloudInitConfigDrive: &kubevirtapiv1.CloudInitConfigDriveSource{UserData: "#cloud-config"},
+		},
+	}}
+	assert.NilError(t, ValidateAccessCredentials(spec))
+}
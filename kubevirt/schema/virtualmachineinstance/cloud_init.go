@@ -0,0 +1,326 @@
+package virtualmachineinstance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/k8s"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+const cloudInitDataHashPrefix = "sha256:"
+
+// Options of cloud-init volume sources that only affect how the provider handles the
+// payloads, and which are therefore not part of the API object.
+const (
+	CloudInitHashInState   = "hash_in_state"
+	CloudInitStoreInSecret = "store_in_secret"
+)
+
+var cloudInitOptions = []string{CloudInitHashInState, CloudInitStoreInSecret}
+
+// cloudInitDataFields are the inline cloud-init payloads, which are hashed when hash_in_state is set.
+var cloudInitDataFields = []string{"user_data", "user_data_base64", "network_data", "network_data_base64"}
+
+// cloudInitSourceKeys are the volume sources holding cloud-init data.
+var cloudInitSourceKeys = []string{"cloud_init_config_drive", "cloud_init_no_cloud"}
+
+// cloudInitSourceSchema describes a cloud-init volume source. Config drive and NoCloud sources
+// share the same fields and only differ in how the data is presented to the guest.
+func cloudInitSourceSchema(description string, kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user_data_secret_ref": k8s.LocalObjectReferenceSchema(fmt.Sprintf("UserDataSecretRef references a k8s secret that contains %s userdata.", kind)),
+				"user_data_base64": {
+					Type:             schema.TypeString,
+					Description:      fmt.Sprintf("UserDataBase64 contains %s cloud-init userdata as a base64 encoded string.", kind),
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: cloudInitDataDiffSuppress,
+				},
+				"user_data": {
+					Type:             schema.TypeString,
+					Description:      fmt.Sprintf("UserData contains %s inline cloud-init userdata.", kind),
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: cloudInitDataDiffSuppress,
+				},
+				"network_data_secret_ref": k8s.LocalObjectReferenceSchema(fmt.Sprintf("NetworkDataSecretRef references a k8s secret that contains %s networkdata.", kind)),
+				"network_data_base64": {
+					Type:             schema.TypeString,
+					Description:      fmt.Sprintf("NetworkDataBase64 contains %s cloud-init networkdata as a base64 encoded string.", kind),
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: cloudInitDataDiffSuppress,
+				},
+				"network_data": {
+					Type:             schema.TypeString,
+					Description:      fmt.Sprintf("NetworkData contains %s inline cloud-init networkdata.", kind),
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: cloudInitDataDiffSuppress,
+				},
+				CloudInitStoreInSecret: {
+					Type:        schema.TypeBool,
+					Description: "Store the inline user data and network data in a Secret owned by the provider, named after the virtual machine, instead of inline in the virtual machine. This is done regardless of this flag when the data exceeds the 2 KiB KubeVirt allows inline.",
+					Optional:    true,
+					Default:     false,
+				},
+				CloudInitHashInState: {
+					Type:        schema.TypeBool,
+					Description: "Store only a SHA-256 hash of the inline user data and network data in the Terraform state. Drift is detected by comparing the hash of the configured data with the hash of the data on the live object.",
+					Optional:    true,
+					Default:     false,
+				},
+			},
+		},
+	}
+}
+
+// expandCloudInitSource expands a cloud-init volume source. NoCloud sources are converted from
+// the identical config drive source.
+func expandCloudInitSource(cloudInitSource []interface{}) *kubevirtapiv1.CloudInitConfigDriveSource {
+	if len(cloudInitSource) == 0 || cloudInitSource[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.CloudInitConfigDriveSource{}
+	in := cloudInitSource[0].(map[string]interface{})
+
+	if v, ok := in["user_data_secret_ref"].([]interface{}); ok {
+		result.UserDataSecretRef = k8s.ExpandLocalObjectReferences(v)
+	}
+	if v, ok := in["user_data_base64"].(string); ok {
+		result.UserDataBase64 = v
+	}
+	if v, ok := in["user_data"].(string); ok {
+		result.UserData = v
+	}
+	if v, ok := in["network_data_secret_ref"].([]interface{}); ok {
+		result.NetworkDataSecretRef = k8s.ExpandLocalObjectReferences(v)
+	}
+	if v, ok := in["network_data_base64"].(string); ok {
+		result.NetworkDataBase64 = v
+	}
+	if v, ok := in["network_data"].(string); ok {
+		result.NetworkData = v
+	}
+
+	return result
+}
+
+func flattenCloudInitSource(in kubevirtapiv1.CloudInitConfigDriveSource) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.UserDataSecretRef != nil {
+		att["user_data_secret_ref"] = k8s.FlattenLocalObjectReferences(*in.UserDataSecretRef)
+	}
+	att["user_data_base64"] = in.UserDataBase64
+	att["user_data"] = in.UserData
+	if in.NetworkDataSecretRef != nil {
+		att["network_data_secret_ref"] = k8s.FlattenLocalObjectReferences(*in.NetworkDataSecretRef)
+	}
+	att["network_data_base64"] = in.NetworkDataBase64
+	att["network_data"] = in.NetworkData
+
+	return []interface{}{att}
+}
+
+// CloudInitSource returns the cloud-init source of the volume along with the name of its API
+// field, or nil if the volume has none. NoCloud sources are returned as the identical config
+// drive source, so both can be handled alike.
+func CloudInitSource(volume *kubevirtapiv1.Volume) (*kubevirtapiv1.CloudInitConfigDriveSource, string) {
+	if volume.CloudInitConfigDrive != nil {
+		return volume.CloudInitConfigDrive, "cloudInitConfigDrive"
+	}
+	if volume.CloudInitNoCloud != nil {
+		return (*kubevirtapiv1.CloudInitConfigDriveSource)(volume.CloudInitNoCloud), "cloudInitNoCloud"
+	}
+	return nil, ""
+}
+
+// ValidateCloudInit checks that the vmi has at most one cloud-init volume, and that each of its
+// payloads is set by exactly one of the inline data, the base64 encoded data or the secret reference.
+func ValidateCloudInit(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+	var volumes []string
+	for i := range spec.Volumes {
+		source, _ := CloudInitSource(&spec.Volumes[i])
+		if source == nil {
+			continue
+		}
+		name := spec.Volumes[i].Name
+		volumes = append(volumes, name)
+
+		userData := countSet(source.UserData != "", source.UserDataBase64 != "", source.UserDataSecretRef != nil)
+		networkData := countSet(source.NetworkData != "", source.NetworkDataBase64 != "", source.NetworkDataSecretRef != nil)
+		if userData > 1 {
+			return fmt.Errorf("volume %s: only one of user_data, user_data_base64 or user_data_secret_ref may be set", name)
+		}
+		if networkData > 1 {
+			return fmt.Errorf("volume %s: only one of network_data, network_data_base64 or network_data_secret_ref may be set", name)
+		}
+		if userData == 0 && networkData == 0 {
+			return fmt.Errorf("volume %s: either user data or network data must be set", name)
+		}
+	}
+	if len(volumes) > 1 {
+		return fmt.Errorf("only one cloud-init volume may be set, got %s", strings.Join(volumes, ", "))
+	}
+	return nil
+}
+
+func countSet(values ...bool) int {
+	count := 0
+	for _, v := range values {
+		if v {
+			count++
+		}
+	}
+	return count
+}
+
+func hashCloudInitData(data string) string {
+	if data == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(data))
+	return cloudInitDataHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// cloudInitDataDiffSuppress ignores the difference between a configured cloud-init payload
+// and its hash in the state, when the source is configured with hash_in_state.
+func cloudInitDataDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	hashInState, _ := d.Get(k[:strings.LastIndex(k, ".")+1] + CloudInitHashInState).(bool)
+	return hashInState && old != "" && old == hashCloudInitData(new)
+}
+
+// CloudInitVolumeSource returns the configured cloud-init source of the volume with the given
+// name, from the template spec found under key, or nil if there is none.
+func CloudInitVolumeSource(resourceData utils.ResourceGetter, key string, volumeName string) map[string]interface{} {
+	configured, _ := resourceData.Get(key + "spec.0.volume").([]interface{})
+	for _, v := range configured {
+		volume, ok := v.(map[string]interface{})
+		if !ok || volume["name"] != volumeName {
+			continue
+		}
+		return cloudInitVolumeSource(volume)
+	}
+	return nil
+}
+
+// CloudInitVolumeOption returns the value of a cloud-init option of the volume with the given
+// name, from the template spec found under key.
+func CloudInitVolumeOption(resourceData utils.ResourceGetter, key string, volumeName string, option string) bool {
+	value, _ := CloudInitVolumeSource(resourceData, key, volumeName)[option].(bool)
+	return value
+}
+
+// ApplyCloudInitStateOptions carries the cloud-init options over from the configuration found under
+// key into a flattened template spec, and replaces the inline payloads with their hashes for the
+// volumes configured with hash_in_state.
+func ApplyCloudInitStateOptions(template []interface{}, resourceData utils.ResourceGetter, key string) {
+	if len(template) == 0 || template[0] == nil {
+		return
+	}
+	spec, _ := template[0].(map[string]interface{})["spec"].([]interface{})
+	if len(spec) == 0 || spec[0] == nil {
+		return
+	}
+	volumes, _ := spec[0].(map[string]interface{})["volume"].([]interface{})
+
+	for _, v := range volumes {
+		volume := v.(map[string]interface{})
+		source := cloudInitVolumeSource(volume)
+		if source == nil {
+			continue
+		}
+		name := volume["name"].(string)
+		for _, option := range cloudInitOptions {
+			source[option] = CloudInitVolumeOption(resourceData, key, name, option)
+		}
+		if !source[CloudInitHashInState].(bool) {
+			continue
+		}
+		for _, field := range cloudInitDataFields {
+			if data, ok := source[field].(string); ok {
+				source[field] = hashCloudInitData(data)
+			}
+		}
+	}
+}
+
+// ApplyCloudInitConfigData replaces the inline payloads of the cloud-init volumes configured with
+// hash_in_state by the payloads of the raw configuration found under key. Unchanged payloads are
+// planned as the hashes kept in the state, which must never be sent to the cluster.
+func ApplyCloudInitConfigData(spec *kubevirtapiv1.VirtualMachineInstanceSpec, config cty.Value, key string) {
+	volumes := utils.ConfigValue(config, key+"spec.0.volume")
+	if volumes.IsNull() || !volumes.IsKnown() || !volumes.CanIterateElements() {
+		return
+	}
+	for i := range spec.Volumes {
+		source, _ := CloudInitSource(&spec.Volumes[i])
+		if source == nil {
+			continue
+		}
+		configured := cloudInitConfigSource(volumes, spec.Volumes[i].Name)
+		if configured.IsNull() || !configured.IsKnown() {
+			continue
+		}
+		if hashInState := configured.GetAttr(CloudInitHashInState); hashInState.IsNull() || !hashInState.IsKnown() || hashInState.False() {
+			continue
+		}
+		source.UserData = configString(configured, "user_data")
+		source.UserDataBase64 = configString(configured, "user_data_base64")
+		source.NetworkData = configString(configured, "network_data")
+		source.NetworkDataBase64 = configString(configured, "network_data_base64")
+	}
+}
+
+// cloudInitConfigSource returns the configured cloud-init source of the volume with the given name,
+// or a null value if there is none.
+func cloudInitConfigSource(volumes cty.Value, volumeName string) cty.Value {
+	for it := volumes.ElementIterator(); it.Next(); {
+		_, volume := it.Element()
+		if configString(volume, "name") != volumeName {
+			continue
+		}
+		for _, key := range cloudInitSourceKeys {
+			if source := utils.ConfigValue(volume, "volume_source.0."+key+".0"); !source.IsNull() {
+				return source
+			}
+		}
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
+
+func configString(config cty.Value, key string) string {
+	value := utils.ConfigValue(config, key)
+	if value.IsNull() || !value.IsKnown() || value.Type() != cty.String {
+		return ""
+	}
+	return value.AsString()
+}
+
+func cloudInitVolumeSource(volume map[string]interface{}) map[string]interface{} {
+	volumeSource, _ := volume["volume_source"].([]interface{})
+	if len(volumeSource) == 0 || volumeSource[0] == nil {
+		return nil
+	}
+	for _, key := range cloudInitSourceKeys {
+		source, _ := volumeSource[0].(map[string]interface{})[key].([]interface{})
+		if len(source) > 0 && source[0] != nil {
+			return source[0].(map[string]interface{})
+		}
+	}
+	return nil
+}
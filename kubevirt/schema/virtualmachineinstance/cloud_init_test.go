@@ -0,0 +1,162 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	k8sv1 "k8s.io/api/core/v1"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateCloudInit(t *testing.T) {
+	noCloud := func(name string, source kubevirtapiv1.CloudInitNoCloudSource) kubevirtapiv1.Volume {
+		return kubevirtapiv1.Volume{
+			Name:         name,
+			VolumeSource: kubevirtapiv1.VolumeSource{CloudInitNoCloud: &source},
+		}
+	}
+	configDrive := func(name string, source kubevirtapiv1.CloudInitConfigDriveSource) kubevirtapiv1.Volume {
+		return kubevirtapiv1.Volume{
+			Name:         name,
+			VolumeSource: kubevirtapiv1.VolumeSource{CloudInitConfigDrive: &source},
+		}
+	}
+
+	cases := []struct {
+		name                 string
+		volumes              []kubevirtapiv1.Volume
+		expectedErrorMessage string
+	}{
+		{
+			name:    "inline no cloud user data",
+			volumes: []kubevirtapiv1.Volume{noCloud("cloudinit", kubevirtapiv1.CloudInitNoCloudSource{UserData: "#cloud-config"})},
+		},
+		{
+			name: "config drive network data from a secret",
+			volumes: []kubevirtapiv1.Volume{configDrive("cloudinit", kubevirtapiv1.CloudInitConfigDriveSource{
+				UserDataBase64:       "I2Nsb3VkLWNvbmZpZw==",
+				NetworkDataSecretRef: &k8sv1.LocalObjectReference{Name: "network-data"},
+			})},
+		},
+		{
+			name: "user data set twice",
+			volumes: []kubevirtapiv1.Volume{noCloud("cloudinit", kubevirtapiv1.CloudInitNoCloudSource{
+				UserData:          "#cloud-config",
+				UserDataSecretRef: &k8sv1.LocalObjectReference{Name: "user-data"},
+			})},
+			expectedErrorMessage: "volume cloudinit: only one of user_data, user_data_base64 or user_data_secret_ref may be set",
+		},
+		{
+			name: "network data set twice",
+			volumes: []kubevirtapiv1.Volume{configDrive("cloudinit", kubevirtapiv1.CloudInitConfigDriveSource{
+				NetworkData:       "network_data",
+				NetworkDataBase64: "bmV0d29ya19kYXRh",
+			})},
+			expectedErrorMessage: "volume cloudinit: only one of network_data, network_data_base64 or network_data_secret_ref may be set",
+		},
+		{
+			name:                 "no payload",
+			volumes:              []kubevirtapiv1.Volume{noCloud("cloudinit", kubevirtapiv1.CloudInitNoCloudSource{})},
+			expectedErrorMessage: "volume cloudinit: either user data or network data must be set",
+		},
+		{
+			name: "two cloud-init volumes",
+			volumes: []kubevirtapiv1.Volume{
+				noCloud("nocloud", kubevirtapiv1.CloudInitNoCloudSource{UserData: "#cloud-config"}),
+				configDrive("configdrive", kubevirtapiv1.CloudInitConfigDriveSource{UserData: "#cloud-config"}),
+			},
+			expectedErrorMessage: "only one cloud-init volume may be set, got nocloud, configdrive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCloudInit(kubevirtapiv1.VirtualMachineInstanceSpec{Volumes: tc.volumes})
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestApplyCloudInitConfigData(t *testing.T) {
+	const userData = "#cloud-config\npassword: fedora"
+	configuredVolumes := func(hashInState bool) cty.Value {
+		return cty.TupleVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("rootdisk"),
+				"volume_source": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+					"data_volume": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+						"name": cty.StringVal("fedora-38"),
+					})}),
+				})}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("cloudinit"),
+				"volume_source": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+					"cloud_init_no_cloud": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+						"user_data":          cty.StringVal(userData),
+						"network_data":       cty.NullVal(cty.String),
+						CloudInitHashInState: cty.BoolVal(hashInState),
+					})}),
+				})}),
+			}),
+		})
+	}
+	config := func(hashInState bool) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"spec": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+				"volume": configuredVolumes(hashInState),
+			})}),
+		})
+	}
+	// The planned spec when only the data volume changed: the unchanged user data is planned
+	// as the hash kept in the state.
+	plannedSpec := func() kubevirtapiv1.VirtualMachineInstanceSpec {
+		return kubevirtapiv1.VirtualMachineInstanceSpec{
+			Volumes: []kubevirtapiv1.Volume{
+				{
+					Name:         "rootdisk",
+					VolumeSource: kubevirtapiv1.VolumeSource{DataVolume: &kubevirtapiv1.DataVolumeSource{Name: "fedora-38"}},
+				},
+				{
+					Name: "cloudinit",
+					VolumeSource: kubevirtapiv1.VolumeSource{CloudInitNoCloud: &kubevirtapiv1.CloudInitNoCloudSource{
+						UserData: hashCloudInitData(userData),
+					}},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name             string
+		hashInState      bool
+		expectedUserData string
+	}{
+		{
+			name:             "unrelated volume updated with hash_in_state",
+			hashInState:      true,
+			expectedUserData: userData,
+		},
+		{
+			name:             "without hash_in_state",
+			hashInState:      false,
+			expectedUserData: hashCloudInitData(userData),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := plannedSpec()
+			ApplyCloudInitConfigData(&spec, config(tc.hashInState), "")
+			assert.DeepEqual(t, spec.Volumes[0], plannedSpec().Volumes[0])
+			assert.Equal(t, spec.Volumes[1].CloudInitNoCloud.UserData, tc.expectedUserData)
+			assert.Equal(t, spec.Volumes[1].CloudInitNoCloud.NetworkData, "")
+		})
+	}
+}
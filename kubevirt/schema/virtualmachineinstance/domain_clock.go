@@ -0,0 +1,270 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func clockSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Clock sets the clock and timers of the vmi. Only one of timezone or utc may be set.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"timezone": {
+					Type:          schema.TypeString,
+					Description:   "Timezone sets the guest clock to the specified timezone. Zone name follows the TZ environment variable format (e.g. 'America/New_York').",
+					Optional:      true,
+					ConflictsWith: []string{specKey + "domain.0.clock.0.utc"},
+				},
+				"utc": {
+					Type:          schema.TypeList,
+					Description:   "UTC sets the guest clock to UTC on each boot. If an offset is specified, guest changes to the clock will be kept during reboots and are not reset.",
+					MaxItems:      1,
+					Optional:      true,
+					ConflictsWith: []string{specKey + "domain.0.clock.0.timezone"},
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"offset_seconds": {
+								Type:        schema.TypeInt,
+								Description: "OffsetSeconds specifies an offset in seconds, relative to UTC. If set, guest changes to the clock will be kept during reboots and not reset.",
+								Optional:    true,
+							},
+						},
+					},
+				},
+				"timer": {
+					Type:        schema.TypeList,
+					Description: "Timer specifies which timers are attached to the vmi.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"hpet": timerSchema("HPET (High Precision Event Timer) - multiple timers with periodic interrupts.", []string{
+								string(kubevirtapiv1.HPETTickPolicyDelay),
+								string(kubevirtapiv1.HPETTickPolicyCatchup),
+								string(kubevirtapiv1.HPETTickPolicyMerge),
+								string(kubevirtapiv1.HPETTickPolicyDiscard),
+							}, false),
+							"kvm": timerSchema("KVM (KVM clock) - lets guests read the host's wall clock time (paravirtualized). For linux guests.", nil, false),
+							"pit": timerSchema("PIT (Programmable Interval Timer) - a timer with periodic interrupts.", []string{
+								string(kubevirtapiv1.PITTickPolicyDelay),
+								string(kubevirtapiv1.PITTickPolicyCatchup),
+								string(kubevirtapiv1.PITTickPolicyDiscard),
+							}, false),
+							"rtc": timerSchema("RTC (Real Time Clock) - a continuously running timer with periodic interrupts.", []string{
+								string(kubevirtapiv1.RTCTickPolicyDelay),
+								string(kubevirtapiv1.RTCTickPolicyCatchup),
+							}, true),
+							"hyperv": timerSchema("Hyperv (Hypervclock) - lets guests read the host's wall clock time (paravirtualized). For windows guests.", nil, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// timerSchema describes a timer, attached to the vmi by the presence of its block unless
+// explicitly disabled, with the tick policies it supports and whether it tracks a clock.
+func timerSchema(description string, tickPolicies []string, track bool) *schema.Schema {
+	fields := map[string]*schema.Schema{
+		"enabled": {
+			Type:        schema.TypeBool,
+			Description: "Enabled set to false makes sure that the machine type or a preset can't add the timer. Defaults to true.",
+			Optional:    true,
+			Default:     true,
+		},
+	}
+	if len(tickPolicies) > 0 {
+		fields["tick_policy"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Description:  fmt.Sprintf("TickPolicy determines what happens when QEMU misses a deadline for injecting a tick to the guest. One of %q.", tickPolicies),
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(tickPolicies, false),
+		}
+	}
+	if track {
+		fields["track"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Description: "Track the guest or the wall clock.",
+			Optional:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(kubevirtapiv1.TrackGuest),
+				string(kubevirtapiv1.TrackWall),
+			}, false),
+		}
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
+func expandClock(clock []interface{}) *kubevirtapiv1.Clock {
+	if len(clock) == 0 || clock[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Clock{}
+	in := clock[0].(map[string]interface{})
+
+	if v, ok := in["timezone"].(string); ok && v != "" {
+		timezone := kubevirtapiv1.ClockOffsetTimezone(v)
+		result.Timezone = &timezone
+	}
+	if v, ok := in["utc"].([]interface{}); ok && len(v) > 0 {
+		result.UTC = &kubevirtapiv1.ClockOffsetUTC{}
+		if v[0] != nil {
+			offsetSeconds := v[0].(map[string]interface{})["offset_seconds"].(int)
+			result.UTC.OffsetSeconds = &offsetSeconds
+		}
+	}
+	if v, ok := in["timer"].([]interface{}); ok {
+		result.Timer = expandTimer(v)
+	}
+
+	return result
+}
+
+func expandTimer(timer []interface{}) *kubevirtapiv1.Timer {
+	if len(timer) == 0 || timer[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Timer{}
+	in := timer[0].(map[string]interface{})
+
+	if enabled, attrs, ok := expandTimerAttributes(in["hpet"]); ok {
+		result.HPET = &kubevirtapiv1.HPETTimer{
+			Enabled:    enabled,
+			TickPolicy: kubevirtapiv1.HPETTickPolicy(attrs["tick_policy"]),
+		}
+	}
+	if enabled, _, ok := expandTimerAttributes(in["kvm"]); ok {
+		result.KVM = &kubevirtapiv1.KVMTimer{
+			Enabled: enabled,
+		}
+	}
+	if enabled, attrs, ok := expandTimerAttributes(in["pit"]); ok {
+		result.PIT = &kubevirtapiv1.PITTimer{
+			Enabled:    enabled,
+			TickPolicy: kubevirtapiv1.PITTickPolicy(attrs["tick_policy"]),
+		}
+	}
+	if enabled, attrs, ok := expandTimerAttributes(in["rtc"]); ok {
+		result.RTC = &kubevirtapiv1.RTCTimer{
+			Enabled:    enabled,
+			TickPolicy: kubevirtapiv1.RTCTickPolicy(attrs["tick_policy"]),
+			Track:      kubevirtapiv1.RTCTimerTrack(attrs["track"]),
+		}
+	}
+	if enabled, _, ok := expandTimerAttributes(in["hyperv"]); ok {
+		result.Hyperv = &kubevirtapiv1.HypervTimer{
+			Enabled: enabled,
+		}
+	}
+
+	return result
+}
+
+// expandTimerAttributes returns whether the timer is enabled and its string attributes, and
+// false if the timer is not configured.
+func expandTimerAttributes(timer interface{}) (*bool, map[string]string, bool) {
+	l, ok := timer.([]interface{})
+	if !ok || len(l) == 0 {
+		return nil, nil, false
+	}
+
+	attrs := make(map[string]string)
+	if l[0] == nil {
+		return nil, attrs, true
+	}
+
+	var enabled *bool
+	for k, v := range l[0].(map[string]interface{}) {
+		switch v := v.(type) {
+		case bool:
+			if k == "enabled" {
+				enabled = &v
+			}
+		case string:
+			attrs[k] = v
+		}
+	}
+
+	return enabled, attrs, true
+}
+
+func flattenClock(in kubevirtapiv1.Clock) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.Timezone != nil {
+		att["timezone"] = string(*in.Timezone)
+	}
+	if in.UTC != nil {
+		utc := map[string]interface{}{
+			"offset_seconds": 0,
+		}
+		if in.UTC.OffsetSeconds != nil {
+			utc["offset_seconds"] = *in.UTC.OffsetSeconds
+		}
+		att["utc"] = []interface{}{utc}
+	}
+	if in.Timer != nil {
+		att["timer"] = flattenTimer(*in.Timer)
+	}
+
+	return []interface{}{att}
+}
+
+func flattenTimer(in kubevirtapiv1.Timer) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.HPET != nil {
+		att["hpet"] = flattenTimerAttributes(in.HPET.Enabled, map[string]string{
+			"tick_policy": string(in.HPET.TickPolicy),
+		})
+	}
+	if in.KVM != nil {
+		att["kvm"] = flattenTimerAttributes(in.KVM.Enabled, nil)
+	}
+	if in.PIT != nil {
+		att["pit"] = flattenTimerAttributes(in.PIT.Enabled, map[string]string{
+			"tick_policy": string(in.PIT.TickPolicy),
+		})
+	}
+	if in.RTC != nil {
+		att["rtc"] = flattenTimerAttributes(in.RTC.Enabled, map[string]string{
+			"tick_policy": string(in.RTC.TickPolicy),
+			"track":       string(in.RTC.Track),
+		})
+	}
+	if in.Hyperv != nil {
+		att["hyperv"] = flattenTimerAttributes(in.Hyperv.Enabled, nil)
+	}
+
+	return []interface{}{att}
+}
+
+func flattenTimerAttributes(enabled *bool, attrs map[string]string) []interface{} {
+	att := make(map[string]interface{})
+
+	att["enabled"] = enabled == nil || *enabled
+	for k, v := range attrs {
+		att[k] = v
+	}
+
+	return []interface{}{att}
+}
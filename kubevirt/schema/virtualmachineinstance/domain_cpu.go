@@ -0,0 +1,254 @@
+package virtualmachineinstance
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func cpuSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "CPU allows specifying the CPU topology.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cores": {
+					Type:         schema.TypeInt,
+					Description:  "Cores specifies the number of cores inside the vmi. Must be a value greater or equal 1.",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"sockets": {
+					Type:         schema.TypeInt,
+					Description:  "Sockets specifies the number of sockets inside the vmi. Must be a value greater or equal 1.",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"threads": {
+					Type:         schema.TypeInt,
+					Description:  "Threads specifies the number of threads inside the vmi. Must be a value greater or equal 1.",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"model": {
+					Type:        schema.TypeString,
+					Description: "Model specifies the CPU model inside the VMI. List of available models https://github.com/libvirt/libvirt/tree/master/src/cpu_map. It is possible to specify special cases like \"host-passthrough\" to get the same CPU as the node and \"host-model\" to get CPU closest to the node one.",
+					Optional:    true,
+				},
+				"feature": {
+					Type:        schema.TypeList,
+					Description: "Features specifies the CPU features list inside the VMI.",
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:        schema.TypeString,
+								Description: "Name of the CPU feature.",
+								Required:    true,
+							},
+							"policy": {
+								Type:        schema.TypeString,
+								Description: "Policy is the CPU feature attribute which can have the following attributes: force, require, optional, disable, forbid. Defaults to require.",
+								Optional:    true,
+								ValidateFunc: validation.StringInSlice([]string{
+									"force",
+									"require",
+									"optional",
+									"disable",
+									"forbid",
+								}, false),
+							},
+						},
+					},
+				},
+				"dedicated_cpu_placement": {
+					Type:        schema.TypeBool,
+					Description: "DedicatedCPUPlacement requests the scheduler to place the VirtualMachineInstance on a node with enough dedicated pCPUs and pin the vCPUs to it.",
+					Optional:    true,
+				},
+				"isolate_emulator_thread": {
+					Type:        schema.TypeBool,
+					Description: "IsolateEmulatorThread requests one more dedicated pCPU to be allocated for the VMI to place the emulator thread on it. Requires dedicated_cpu_placement.",
+					Optional:    true,
+				},
+				"numa": {
+					Type:        schema.TypeList,
+					Description: "NUMA allows specifying settings for the guest NUMA topology.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"guest_mapping_passthrough": {
+								Type:        schema.TypeBool,
+								Description: "GuestMappingPassthrough will create an efficient guest topology based on host CPUs exclusively assigned to a pod. The created topology ensures that memory and CPUs on the virtual numa nodes never cross boundaries of host numa nodes. Requires dedicated_cpu_placement.",
+								Optional:    true,
+							},
+						},
+					},
+				},
+				"realtime": {
+					Type:        schema.TypeList,
+					Description: "Realtime instructs the virt-launcher to tune the VMI for lower latency, optional for real time workloads. Requires dedicated_cpu_placement.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"mask": {
+								Type:        schema.TypeString,
+								Description: "Mask defines the vcpu mask expression that defines which vcpus are used for realtime. Format matches libvirt's expressions. Example: \"0-3,^1\",\"0,2,3\",\"2-3\".",
+								Optional:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandCPU(cpu []interface{}) *kubevirtapiv1.CPU {
+	if len(cpu) == 0 || cpu[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.CPU{}
+	in := cpu[0].(map[string]interface{})
+
+	if v, ok := in["cores"].(int); ok {
+		result.Cores = uint32(v)
+	}
+	if v, ok := in["sockets"].(int); ok {
+		result.Sockets = uint32(v)
+	}
+	if v, ok := in["threads"].(int); ok {
+		result.Threads = uint32(v)
+	}
+	if v, ok := in["model"].(string); ok {
+		result.Model = v
+	}
+	if v, ok := in["feature"].([]interface{}); ok {
+		result.Features = expandCPUFeatures(v)
+	}
+	if v, ok := in["dedicated_cpu_placement"].(bool); ok {
+		result.DedicatedCPUPlacement = v
+	}
+	if v, ok := in["isolate_emulator_thread"].(bool); ok {
+		result.IsolateEmulatorThread = v
+	}
+	if v, ok := in["numa"].([]interface{}); ok {
+		result.NUMA = expandNUMA(v)
+	}
+	if v, ok := in["realtime"].([]interface{}); ok {
+		result.Realtime = expandRealtime(v)
+	}
+
+	return result
+}
+
+func expandCPUFeatures(features []interface{}) []kubevirtapiv1.CPUFeature {
+	if len(features) == 0 || features[0] == nil {
+		return nil
+	}
+
+	result := make([]kubevirtapiv1.CPUFeature, len(features))
+
+	for i, feature := range features {
+		in := feature.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["policy"].(string); ok {
+			result[i].Policy = v
+		}
+	}
+
+	return result
+}
+
+func expandNUMA(numa []interface{}) *kubevirtapiv1.NUMA {
+	if len(numa) == 0 || numa[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.NUMA{}
+	in := numa[0].(map[string]interface{})
+
+	if v, ok := in["guest_mapping_passthrough"].(bool); ok && v {
+		result.GuestMappingPassthrough = &kubevirtapiv1.NUMAGuestMappingPassthrough{}
+	}
+
+	return result
+}
+
+func expandRealtime(realtime []interface{}) *kubevirtapiv1.Realtime {
+	// An empty block enables realtime with the default mask.
+	if len(realtime) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Realtime{}
+	if realtime[0] == nil {
+		return result
+	}
+	in := realtime[0].(map[string]interface{})
+
+	if v, ok := in["mask"].(string); ok {
+		result.Mask = v
+	}
+
+	return result
+}
+
+func flattenCPU(in kubevirtapiv1.CPU) []interface{} {
+	att := make(map[string]interface{})
+
+	att["cores"] = int(in.Cores)
+	att["sockets"] = int(in.Sockets)
+	att["threads"] = int(in.Threads)
+	att["model"] = in.Model
+	att["feature"] = flattenCPUFeatures(in.Features)
+	att["dedicated_cpu_placement"] = in.DedicatedCPUPlacement
+	att["isolate_emulator_thread"] = in.IsolateEmulatorThread
+	if in.NUMA != nil {
+		att["numa"] = flattenNUMA(*in.NUMA)
+	}
+	if in.Realtime != nil {
+		att["realtime"] = flattenRealtime(*in.Realtime)
+	}
+
+	return []interface{}{att}
+}
+
+func flattenCPUFeatures(in []kubevirtapiv1.CPUFeature) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		c["policy"] = v.Policy
+
+		att[i] = c
+	}
+
+	return att
+}
+
+func flattenNUMA(in kubevirtapiv1.NUMA) []interface{} {
+	att := make(map[string]interface{})
+
+	att["guest_mapping_passthrough"] = in.GuestMappingPassthrough != nil
+
+	return []interface{}{att}
+}
+
+func flattenRealtime(in kubevirtapiv1.Realtime) []interface{} {
+	att := make(map[string]interface{})
+
+	att["mask"] = in.Mask
+
+	return []interface{}{att}
+}
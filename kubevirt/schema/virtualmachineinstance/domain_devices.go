@@ -0,0 +1,375 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+// deviceToggles are the optional boolean device settings, with the value KubeVirt assumes when
+// they are not set. Only values differing from the default are sent to KubeVirt.
+var deviceToggles = []struct {
+	key          string
+	description  string
+	defaultValue bool
+	field        func(*kubevirtapiv1.Devices) **bool
+}{
+	{
+		key:          "use_virtio_transitional",
+		description:  "Fall back to the virtio transitional model for virtio devices, to support old guests such as CentOS 6.",
+		defaultValue: false,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.UseVirtioTransitional },
+	},
+	{
+		key:          "network_interface_multiqueue",
+		description:  "Create a queue per vCPU for the virtio network interfaces.",
+		defaultValue: false,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.NetworkInterfaceMultiQueue },
+	},
+	{
+		key:          "block_multi_queue",
+		description:  "Create a queue per vCPU for the virtio disks.",
+		defaultValue: false,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.BlockMultiQueue },
+	},
+	{
+		key:          "autoattach_graphics_device",
+		description:  "Attach the default graphics device. Set to false for headless virtual machines.",
+		defaultValue: true,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.AutoattachGraphicsDevice },
+	},
+	{
+		key:          "autoattach_serial_console",
+		description:  "Attach the default serial console.",
+		defaultValue: true,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.AutoattachSerialConsole },
+	},
+	{
+		key:          "autoattach_pod_interface",
+		description:  "Attach the default pod network interface when no interfaces are set.",
+		defaultValue: true,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.AutoattachPodInterface },
+	},
+	{
+		key:          "autoattach_mem_balloon",
+		description:  "Attach the memory balloon device.",
+		defaultValue: true,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.AutoattachMemBalloon },
+	},
+	{
+		key:          "autoattach_vsock",
+		description:  "Attach a VSOCK CID to the vmi. Requires the VSOCK feature gate.",
+		defaultValue: false,
+		field:        func(d *kubevirtapiv1.Devices) **bool { return &d.AutoattachVSOCK },
+	},
+}
+
+func devicesSchema() *schema.Schema {
+	fields := map[string]*schema.Schema{
+		"disk":         disksSchema(),
+		"interface":    interfacesSchema(),
+		"gpus":         gpusSchema(),
+		"host_devices": hostDevicesSchema(),
+		"filesystem":   filesystemsSchema(),
+		"tpm": {
+			Type:        schema.TypeList,
+			Description: "TPM attaches an emulated TPM 2.0 device to the vmi, as required by Windows 11.",
+			MaxItems:    1,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{},
+			},
+		},
+		"watchdog": {
+			Type:        schema.TypeList,
+			Description: "Watchdog describes a watchdog device which can be added to the vmi.",
+			MaxItems:    1,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Description: "Name of the watchdog.",
+						Required:    true,
+					},
+					"i6300esb": {
+						Type:        schema.TypeList,
+						Description: "i6300esb watchdog device.",
+						MaxItems:    1,
+						Required:    true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"action": {
+									Type:        schema.TypeString,
+									Description: "The action to take. Valid values are poweroff, reset, shutdown. Defaults to reset.",
+									Optional:    true,
+									ValidateFunc: validation.StringInSlice([]string{
+										string(kubevirtapiv1.WatchdogActionPoweroff),
+										string(kubevirtapiv1.WatchdogActionReset),
+										string(kubevirtapiv1.WatchdogActionShutdown),
+									}, false),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"rng": {
+			Type:        schema.TypeList,
+			Description: "Rng attaches a virtio random number generator, fed from the host, to the vmi.",
+			MaxItems:    1,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{},
+			},
+		},
+		"sound": {
+			Type:        schema.TypeList,
+			Description: "Sound attaches a sound device to the vmi.",
+			MaxItems:    1,
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Description: "User's defined name for this sound device.",
+						Required:    true,
+					},
+					"model": {
+						Type:         schema.TypeString,
+						Description:  "The model of the sound device. One of ich9, ac97. Defaults to ich9.",
+						Optional:     true,
+						ValidateFunc: validation.StringInSlice([]string{"ich9", "ac97"}, false),
+					},
+				},
+			},
+		},
+		"input": {
+			Type:        schema.TypeList,
+			Description: "Inputs describe input devices, such as a tablet for accurate pointer positioning in graphical consoles.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Description: "Name is the device name.",
+						Required:    true,
+					},
+					"type": {
+						Type:         schema.TypeString,
+						Description:  "Type indicates the type of the input device. Only tablet is supported.",
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{string(kubevirtapiv1.InputTypeTablet)}, false),
+					},
+					"bus": {
+						Type:        schema.TypeString,
+						Description: "Bus indicates the bus of the input device to emulate. One of usb, virtio. Defaults to usb.",
+						Optional:    true,
+						ValidateFunc: validation.StringInSlice([]string{
+							string(kubevirtapiv1.InputBusUSB),
+							string(kubevirtapiv1.InputBusVirtio),
+						}, false),
+					},
+				},
+			},
+		},
+	}
+	for _, toggle := range deviceToggles {
+		fields[toggle.key] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Description: fmt.Sprintf("%s Defaults to %t.", toggle.description, toggle.defaultValue),
+			Optional:    true,
+			Default:     toggle.defaultValue,
+		}
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Devices allows adding disks, network interfaces, ...",
+		MaxItems:    1,
+		Required:    true,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
+func expandDevices(devices []interface{}) (kubevirtapiv1.Devices, error) {
+	result := kubevirtapiv1.Devices{}
+
+	if len(devices) == 0 || devices[0] == nil {
+		return result, nil
+	}
+
+	in := devices[0].(map[string]interface{})
+
+	if v, ok := in["disk"].([]interface{}); ok {
+		result.Disks = expandDisks(v)
+	}
+	if v, ok := in["interface"].([]interface{}); ok {
+		result.Interfaces = expandInterfaces(v)
+	}
+	if v, ok := in["gpus"].([]interface{}); ok && len(v) > 0 {
+		result.GPUs = expandGPUs(v)
+	}
+	if v, ok := in["host_devices"].([]interface{}); ok && len(v) > 0 {
+		result.HostDevices = expandHostDevices(v)
+	}
+	if v, ok := in["filesystem"].([]interface{}); ok && len(v) > 0 {
+		result.Filesystems = expandFilesystems(v)
+	}
+	if v, ok := in["tpm"].([]interface{}); ok && len(v) > 0 {
+		result.TPM = &kubevirtapiv1.TPMDevice{}
+	}
+	if v, ok := in["watchdog"].([]interface{}); ok {
+		result.Watchdog = expandWatchdog(v)
+	}
+	if v, ok := in["rng"].([]interface{}); ok && len(v) > 0 {
+		result.Rng = &kubevirtapiv1.Rng{}
+	}
+	if v, ok := in["sound"].([]interface{}); ok {
+		result.Sound = expandSound(v)
+	}
+	if v, ok := in["input"].([]interface{}); ok && len(v) > 0 {
+		result.Inputs = expandInputs(v)
+	}
+	for _, toggle := range deviceToggles {
+		if v, ok := in[toggle.key].(bool); ok && v != toggle.defaultValue {
+			*toggle.field(&result) = &v
+		}
+	}
+
+	return result, nil
+}
+
+func expandWatchdog(watchdog []interface{}) *kubevirtapiv1.Watchdog {
+	if len(watchdog) == 0 || watchdog[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Watchdog{}
+	in := watchdog[0].(map[string]interface{})
+
+	if v, ok := in["name"].(string); ok {
+		result.Name = v
+	}
+	if v, ok := in["i6300esb"].([]interface{}); ok && len(v) > 0 {
+		result.I6300ESB = &kubevirtapiv1.I6300ESBWatchdog{}
+		if v[0] != nil {
+			result.I6300ESB.Action = kubevirtapiv1.WatchdogAction(v[0].(map[string]interface{})["action"].(string))
+		}
+	}
+
+	return result
+}
+
+func expandSound(sound []interface{}) *kubevirtapiv1.SoundDevice {
+	if len(sound) == 0 || sound[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.SoundDevice{}
+	in := sound[0].(map[string]interface{})
+
+	if v, ok := in["name"].(string); ok {
+		result.Name = v
+	}
+	if v, ok := in["model"].(string); ok {
+		result.Model = v
+	}
+
+	return result
+}
+
+func expandInputs(inputs []interface{}) []kubevirtapiv1.Input {
+	result := make([]kubevirtapiv1.Input, len(inputs))
+
+	for i, input := range inputs {
+		in := input.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["type"].(string); ok {
+			result[i].Type = kubevirtapiv1.InputType(v)
+		}
+		if v, ok := in["bus"].(string); ok {
+			result[i].Bus = kubevirtapiv1.InputBus(v)
+		}
+	}
+
+	return result
+}
+
+func flattenDevices(in kubevirtapiv1.Devices) []interface{} {
+	att := make(map[string]interface{})
+
+	att["disk"] = flattenDisks(in.Disks)
+	att["interface"] = flattenInterfaces(in.Interfaces)
+	att["gpus"] = flattenGPUs(in.GPUs)
+	att["host_devices"] = flattenHostDevices(in.HostDevices)
+	att["filesystem"] = flattenFilesystems(in.Filesystems)
+	if in.TPM != nil {
+		att["tpm"] = []interface{}{map[string]interface{}{}}
+	}
+	if in.Watchdog != nil {
+		att["watchdog"] = flattenWatchdog(*in.Watchdog)
+	}
+	if in.Rng != nil {
+		att["rng"] = []interface{}{map[string]interface{}{}}
+	}
+	if in.Sound != nil {
+		att["sound"] = flattenSound(*in.Sound)
+	}
+	att["input"] = flattenInputs(in.Inputs)
+	for _, toggle := range deviceToggles {
+		value := toggle.defaultValue
+		if v := *toggle.field(&in); v != nil {
+			value = *v
+		}
+		att[toggle.key] = value
+	}
+
+	return []interface{}{att}
+}
+
+func flattenWatchdog(in kubevirtapiv1.Watchdog) []interface{} {
+	att := make(map[string]interface{})
+
+	att["name"] = in.Name
+	if in.I6300ESB != nil {
+		att["i6300esb"] = []interface{}{map[string]interface{}{
+			"action": string(in.I6300ESB.Action),
+		}}
+	}
+
+	return []interface{}{att}
+}
+
+func flattenSound(in kubevirtapiv1.SoundDevice) []interface{} {
+	att := make(map[string]interface{})
+
+	att["name"] = in.Name
+	att["model"] = in.Model
+
+	return []interface{}{att}
+}
+
+func flattenInputs(in []kubevirtapiv1.Input) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		c["type"] = string(v.Type)
+		c["bus"] = string(v.Bus)
+
+		att[i] = c
+	}
+
+	return att
+}
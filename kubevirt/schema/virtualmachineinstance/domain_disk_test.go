@@ -0,0 +1,70 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateDisks(t *testing.T) {
+	cases := []struct {
+		name                 string
+		disk                 kubevirtapiv1.Disk
+		expectedErrorMessage string
+	}{
+		{
+			name: "cdrom",
+			disk: kubevirtapiv1.Disk{
+				Name:       "installer",
+				DiskDevice: kubevirtapiv1.DiskDevice{CDRom: &kubevirtapiv1.CDRomTarget{}},
+			},
+		},
+		{
+			name: "disk with a matched block size",
+			disk: kubevirtapiv1.Disk{
+				Name:       "rootdisk",
+				DiskDevice: kubevirtapiv1.DiskDevice{Disk: &kubevirtapiv1.DiskTarget{}},
+				BlockSize:  &kubevirtapiv1.BlockSize{MatchVolume: &kubevirtapiv1.FeatureState{}},
+			},
+		},
+		{
+			name: "disk and lun",
+			disk: kubevirtapiv1.Disk{
+				Name: "rootdisk",
+				DiskDevice: kubevirtapiv1.DiskDevice{
+					Disk: &kubevirtapiv1.DiskTarget{},
+					LUN:  &kubevirtapiv1.LunTarget{},
+				},
+			},
+			expectedErrorMessage: "disk rootdisk: only one of disk, cdrom or lun may be set in the disk device",
+		},
+		{
+			name: "custom and matched block size",
+			disk: kubevirtapiv1.Disk{
+				Name:       "rootdisk",
+				DiskDevice: kubevirtapiv1.DiskDevice{Disk: &kubevirtapiv1.DiskTarget{}},
+				BlockSize: &kubevirtapiv1.BlockSize{
+					Custom:      &kubevirtapiv1.CustomBlockSize{Logical: 512, Physical: 4096},
+					MatchVolume: &kubevirtapiv1.FeatureState{},
+				},
+			},
+			expectedErrorMessage: "disk rootdisk: only one of custom or match_volume may be set in the block size",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := kubevirtapiv1.VirtualMachineInstanceSpec{}
+			spec.Domain.Devices.Disks = []kubevirtapiv1.Disk{tc.disk}
+
+			err := ValidateDisks(spec)
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package virtualmachineinstance
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func featuresSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Features like acpi, apic, hyperv, smm.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"acpi": featureStateSchema("ACPI enables/disables ACPI inside the guest. Defaults to enabled."),
+				"apic": {
+					Type:        schema.TypeList,
+					Description: "Defaults to the machine type setting.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:        schema.TypeBool,
+								Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+								Optional:    true,
+								Default:     true,
+							},
+							"end_of_interrupt": {
+								Type:        schema.TypeBool,
+								Description: "EndOfInterrupt enables the end of interrupt notification in the guest. Defaults to false.",
+								Optional:    true,
+							},
+						},
+					},
+				},
+				"hyperv":     hypervSchema(),
+				"smm":        featureStateSchema("SMM enables/disables System Management Mode. TSEG not yet implemented."),
+				"pvspinlock": featureStateSchema("Notify the guest that the host supports paravirtual spinlocks. For older kernels this feature should be explicitly disabled."),
+				"kvm": {
+					Type:        schema.TypeList,
+					Description: "Configure how KVM presence is exposed to the guest.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"hidden": {
+								Type:        schema.TypeBool,
+								Description: "Hide the KVM hypervisor from standard MSR based discovery. Defaults to false.",
+								Optional:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func hypervSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Defaults to the machine type setting.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"relaxed": featureStateSchema("Relaxed instructs the guest OS to disable watchdog timeouts."),
+				"vapic":   featureStateSchema("VAPIC improves the paravirtualized handling of interrupts."),
+				"spinlocks": {
+					Type:        schema.TypeList,
+					Description: "Spinlocks allows to configure the spinlock retry attempts.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:        schema.TypeBool,
+								Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+								Optional:    true,
+								Default:     true,
+							},
+							"retries": {
+								Type:         schema.TypeInt,
+								Description:  "Retries indicates the number of retries. Must be a value greater or equal 4096. Defaults to 4096.",
+								Optional:     true,
+								ValidateFunc: validation.IntAtLeast(4096),
+							},
+						},
+					},
+				},
+				"vpindex": featureStateSchema("VPIndex enables the Virtual Processor Index to help windows identifying virtual processors."),
+				"runtime": featureStateSchema("Runtime improves the time accounting to improve scheduling in the guest."),
+				"synic":   featureStateSchema("SyNIC enables the Synthetic Interrupt Controller. Requires vpindex."),
+				"stimer": {
+					Type:        schema.TypeList,
+					Description: "SyNICTimer enables Synthetic Interrupt Controller Timers, reducing CPU load. Requires synic.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:        schema.TypeBool,
+								Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+								Optional:    true,
+								Default:     true,
+							},
+							"direct": {
+								Type:        schema.TypeBool,
+								Description: "Direct enables direct mode of the synthetic timers. Defaults to false.",
+								Optional:    true,
+							},
+						},
+					},
+				},
+				"reset": featureStateSchema("Reset enables Hyperv reboot/reset for the vmi. Requires synic."),
+				"vendor_id": {
+					Type:        schema.TypeList,
+					Description: "VendorID allows setting the hypervisor vendor id.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"enabled": {
+								Type:        schema.TypeBool,
+								Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+								Optional:    true,
+								Default:     true,
+							},
+							"vendor_id": {
+								Type:         schema.TypeString,
+								Description:  "VendorID sets the hypervisor vendor id, visible to the vmi. String up to twelve characters.",
+								Optional:     true,
+								ValidateFunc: validation.StringLenBetween(0, 12),
+							},
+						},
+					},
+				},
+				"frequencies":     featureStateSchema("Frequencies improves the TSC clock source handling for Hyper-V on KVM."),
+				"reenlightenment": featureStateSchema("Reenlightenment enables the notifications on TSC frequency changes."),
+				"tlbflush":        featureStateSchema("TLBFlush improves performances in overcommited environments. Requires vpindex."),
+				"ipi":             featureStateSchema("IPI improves performances in overcommited environments. Requires vpindex."),
+				"evmcs":           featureStateSchema("EVMCS speeds up L2 vmexits, but disables other virtualization features. Requires vapic."),
+			},
+		},
+	}
+}
+
+// featureStateSchema describes a feature which is enabled by the presence of its block, unless
+// explicitly disabled.
+func featureStateSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enabled": {
+					Type:        schema.TypeBool,
+					Description: "Enabled determines if the feature should be enabled or disabled on the guest. Defaults to true.",
+					Optional:    true,
+					Default:     true,
+				},
+			},
+		},
+	}
+}
+
+func expandFeatures(features []interface{}) *kubevirtapiv1.Features {
+	if len(features) == 0 || features[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Features{}
+	in := features[0].(map[string]interface{})
+
+	if v, ok := in["acpi"].([]interface{}); ok {
+		if acpi := expandFeatureState(v); acpi != nil {
+			result.ACPI = *acpi
+		}
+	}
+	if v, ok := in["apic"].([]interface{}); ok {
+		result.APIC = expandFeatureAPIC(v)
+	}
+	if v, ok := in["hyperv"].([]interface{}); ok {
+		result.Hyperv = expandFeatureHyperv(v)
+	}
+	if v, ok := in["smm"].([]interface{}); ok {
+		result.SMM = expandFeatureState(v)
+	}
+	if v, ok := in["pvspinlock"].([]interface{}); ok {
+		result.Pvspinlock = expandFeatureState(v)
+	}
+	if v, ok := in["kvm"].([]interface{}); ok && len(v) > 0 {
+		result.KVM = &kubevirtapiv1.FeatureKVM{}
+		if v[0] != nil {
+			result.KVM.Hidden = v[0].(map[string]interface{})["hidden"].(bool)
+		}
+	}
+
+	return result
+}
+
+func expandFeatureState(featureState []interface{}) *kubevirtapiv1.FeatureState {
+	if len(featureState) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.FeatureState{}
+	if featureState[0] == nil {
+		return result
+	}
+	in := featureState[0].(map[string]interface{})
+
+	if v, ok := in["enabled"].(bool); ok {
+		result.Enabled = &v
+	}
+
+	return result
+}
+
+func expandFeatureAPIC(apic []interface{}) *kubevirtapiv1.FeatureAPIC {
+	if len(apic) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.FeatureAPIC{}
+	if apic[0] == nil {
+		return result
+	}
+	in := apic[0].(map[string]interface{})
+
+	if v, ok := in["enabled"].(bool); ok {
+		result.Enabled = &v
+	}
+	if v, ok := in["end_of_interrupt"].(bool); ok {
+		result.EndOfInterrupt = v
+	}
+
+	return result
+}
+
+func expandFeatureHyperv(hyperv []interface{}) *kubevirtapiv1.FeatureHyperv {
+	if len(hyperv) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.FeatureHyperv{}
+	if hyperv[0] == nil {
+		return result
+	}
+	in := hyperv[0].(map[string]interface{})
+
+	if v, ok := in["relaxed"].([]interface{}); ok {
+		result.Relaxed = expandFeatureState(v)
+	}
+	if v, ok := in["vapic"].([]interface{}); ok {
+		result.VAPIC = expandFeatureState(v)
+	}
+	if v, ok := in["spinlocks"].([]interface{}); ok {
+		result.Spinlocks = expandFeatureSpinlocks(v)
+	}
+	if v, ok := in["vpindex"].([]interface{}); ok {
+		result.VPIndex = expandFeatureState(v)
+	}
+	if v, ok := in["runtime"].([]interface{}); ok {
+		result.Runtime = expandFeatureState(v)
+	}
+	if v, ok := in["synic"].([]interface{}); ok {
+		result.SyNIC = expandFeatureState(v)
+	}
+	if v, ok := in["stimer"].([]interface{}); ok {
+		result.SyNICTimer = expandSyNICTimer(v)
+	}
+	if v, ok := in["reset"].([]interface{}); ok {
+		result.Reset = expandFeatureState(v)
+	}
+	if v, ok := in["vendor_id"].([]interface{}); ok {
+		result.VendorID = expandFeatureVendorID(v)
+	}
+	if v, ok := in["frequencies"].([]interface{}); ok {
+		result.Frequencies = expandFeatureState(v)
+	}
+	if v, ok := in["reenlightenment"].([]interface{}); ok {
+		result.Reenlightenment = expandFeatureState(v)
+	}
+	if v, ok := in["tlbflush"].([]interface{}); ok {
+		result.TLBFlush = expandFeatureState(v)
+	}
+	if v, ok := in["ipi"].([]interface{}); ok {
+		result.IPI = expandFeatureState(v)
+	}
+	if v, ok := in["evmcs"].([]interface{}); ok {
+		result.EVMCS = expandFeatureState(v)
+	}
+
+	return result
+}
+
+func expandFeatureSpinlocks(spinlocks []interface{}) *kubevirtapiv1.FeatureSpinlocks {
+	if len(spinlocks) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.FeatureSpinlocks{}
+	if spinlocks[0] == nil {
+		return result
+	}
+	in := spinlocks[0].(map[string]interface{})
+
+	if v, ok := in["enabled"].(bool); ok {
+		result.Enabled = &v
+	}
+	if v, ok := in["retries"].(int); ok && v > 0 {
+		retries := uint32(v)
+		result.Retries = &retries
+	}
+
+	return result
+}
+
+func expandSyNICTimer(stimer []interface{}) *kubevirtapiv1.SyNICTimer {
+	if len(stimer) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.SyNICTimer{}
+	if stimer[0] == nil {
+		return result
+	}
+	in := stimer[0].(map[string]interface{})
+
+	if v, ok := in["enabled"].(bool); ok {
+		result.Enabled = &v
+	}
+	if v, ok := in["direct"].(bool); ok && v {
+		result.Direct = &kubevirtapiv1.FeatureState{Enabled: &v}
+	}
+
+	return result
+}
+
+func expandFeatureVendorID(vendorID []interface{}) *kubevirtapiv1.FeatureVendorID {
+	if len(vendorID) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.FeatureVendorID{}
+	if vendorID[0] == nil {
+		return result
+	}
+	in := vendorID[0].(map[string]interface{})
+
+	if v, ok := in["enabled"].(bool); ok {
+		result.Enabled = &v
+	}
+	if v, ok := in["vendor_id"].(string); ok {
+		result.VendorID = v
+	}
+
+	return result
+}
+
+func flattenFeatures(in kubevirtapiv1.Features) []interface{} {
+	att := make(map[string]interface{})
+
+	// ACPI is not a pointer, it is only considered configured once explicitly enabled or disabled.
+	if in.ACPI.Enabled != nil {
+		att["acpi"] = flattenFeatureState(in.ACPI)
+	}
+	if in.APIC != nil {
+		att["apic"] = flattenFeatureAPIC(*in.APIC)
+	}
+	if in.Hyperv != nil {
+		att["hyperv"] = flattenFeatureHyperv(*in.Hyperv)
+	}
+	if in.SMM != nil {
+		att["smm"] = flattenFeatureState(*in.SMM)
+	}
+	if in.Pvspinlock != nil {
+		att["pvspinlock"] = flattenFeatureState(*in.Pvspinlock)
+	}
+	if in.KVM != nil {
+		att["kvm"] = []interface{}{
+			map[string]interface{}{
+				"hidden": in.KVM.Hidden,
+			},
+		}
+	}
+
+	return []interface{}{att}
+}
+
+func flattenFeatureState(in kubevirtapiv1.FeatureState) []interface{} {
+	att := make(map[string]interface{})
+
+	att["enabled"] = featureEnabled(&in)
+
+	return []interface{}{att}
+}
+
+// featureEnabled reports whether the feature is enabled, which it is by default once present.
+func featureEnabled(in *kubevirtapiv1.FeatureState) bool {
+	return in != nil && (in.Enabled == nil || *in.Enabled)
+}
+
+func flattenFeatureAPIC(in kubevirtapiv1.FeatureAPIC) []interface{} {
+	att := make(map[string]interface{})
+
+	att["enabled"] = in.Enabled == nil || *in.Enabled
+	att["end_of_interrupt"] = in.EndOfInterrupt
+
+	return []interface{}{att}
+}
+
+func flattenFeatureHyperv(in kubevirtapiv1.FeatureHyperv) []interface{} {
+	att := make(map[string]interface{})
+
+	states := map[string]*kubevirtapiv1.FeatureState{
+		"relaxed":         in.Relaxed,
+		"vapic":           in.VAPIC,
+		"vpindex":         in.VPIndex,
+		"runtime":         in.Runtime,
+		"synic":           in.SyNIC,
+		"reset":           in.Reset,
+		"frequencies":     in.Frequencies,
+		"reenlightenment": in.Reenlightenment,
+		"tlbflush":        in.TLBFlush,
+		"ipi":             in.IPI,
+		"evmcs":           in.EVMCS,
+	}
+	for k, v := range states {
+		if v != nil {
+			att[k] = flattenFeatureState(*v)
+		}
+	}
+	if in.Spinlocks != nil {
+		spinlocks := map[string]interface{}{
+			"enabled": in.Spinlocks.Enabled == nil || *in.Spinlocks.Enabled,
+		}
+		if in.Spinlocks.Retries != nil {
+			spinlocks["retries"] = int(*in.Spinlocks.Retries)
+		}
+		att["spinlocks"] = []interface{}{spinlocks}
+	}
+	if in.SyNICTimer != nil {
+		att["stimer"] = []interface{}{
+			map[string]interface{}{
+				"enabled": in.SyNICTimer.Enabled == nil || *in.SyNICTimer.Enabled,
+				"direct":  featureEnabled(in.SyNICTimer.Direct),
+			},
+		}
+	}
+	if in.VendorID != nil {
+		att["vendor_id"] = []interface{}{
+			map[string]interface{}{
+				"enabled":   in.VendorID.Enabled == nil || *in.VendorID.Enabled,
+				"vendor_id": in.VendorID.VendorID,
+			},
+		}
+	}
+
+	return []interface{}{att}
+}
@@ -0,0 +1,97 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func filesystemsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Filesystems describes filesystems which are shared with the vmi through virtiofs. Requires the ExperimentalVirtiofsSupport feature gate.",
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Description: "Name is the device name. It must match the name of a volume.",
+					Required:    true,
+				},
+				"virtiofs": {
+					Type:        schema.TypeList,
+					Description: "Virtiofs shares the volume through virtiofs.",
+					MaxItems:    1,
+					Required:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandFilesystems(filesystems []interface{}) []kubevirtapiv1.Filesystem {
+	result := make([]kubevirtapiv1.Filesystem, len(filesystems))
+
+	for i, filesystem := range filesystems {
+		in := filesystem.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["virtiofs"].([]interface{}); ok && len(v) > 0 {
+			result[i].Virtiofs = &kubevirtapiv1.FilesystemVirtiofs{}
+		}
+	}
+
+	return result
+}
+
+func flattenFilesystems(in []kubevirtapiv1.Filesystem) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		if v.Virtiofs != nil {
+			c["virtiofs"] = []interface{}{map[string]interface{}{}}
+		}
+
+		att[i] = c
+	}
+
+	return att
+}
+
+// ValidateFilesystems checks that every filesystem shares a volume that virtiofs supports, and
+// that the volume is not also attached as a disk.
+func ValidateFilesystems(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+	volumes := make(map[string]kubevirtapiv1.Volume)
+	for _, volume := range spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	disks := make(map[string]bool)
+	for _, disk := range spec.Domain.Devices.Disks {
+		disks[disk.Name] = true
+	}
+
+	for _, filesystem := range spec.Domain.Devices.Filesystems {
+		volume, ok := volumes[filesystem.Name]
+		if !ok {
+			return fmt.Errorf("filesystem %s has no matching volume", filesystem.Name)
+		}
+		if disks[filesystem.Name] {
+			return fmt.Errorf("volume %s cannot be attached both as a disk and as a filesystem", filesystem.Name)
+		}
+		source := volume.VolumeSource
+		if source.PersistentVolumeClaim == nil && source.DataVolume == nil && source.ConfigMap == nil &&
+			source.Secret == nil && source.ServiceAccount == nil && source.DownwardAPI == nil {
+			return fmt.Errorf("filesystem %s: virtiofs only supports persistent_volume_claim, data_volume, config_map, secret, service_account and downward_api volumes", filesystem.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateFilesystems(t *testing.T) {
+	volume := func(name string, source kubevirtapiv1.VolumeSource) kubevirtapiv1.Volume {
+		return kubevirtapiv1.Volume{Name: name, VolumeSource: source}
+	}
+	configMap := kubevirtapiv1.VolumeSource{ConfigMap: &kubevirtapiv1.ConfigMapVolumeSource{}}
+
+	cases := []struct {
+		name                 string
+		disks                []string
+		filesystems          []string
+		volumes              []kubevirtapiv1.Volume
+		expectedErrorMessage string
+	}{
+		{
+			name:        "config map filesystem",
+			filesystems: []string{"config"},
+			volumes:     []kubevirtapiv1.Volume{volume("config", configMap)},
+		},
+		{
+			name:                 "filesystem without volume",
+			filesystems:          []string{"config"},
+			expectedErrorMessage: "filesystem config has no matching volume",
+		},
+		{
+			name:                 "volume attached as disk and filesystem",
+			disks:                []string{"config"},
+			filesystems:          []string{"config"},
+			volumes:              []kubevirtapiv1.Volume{volume("config", configMap)},
+			expectedErrorMessage: "volume config cannot be attached both as a disk and as a filesystem",
+		},
+		{
+			name:                 "container disk filesystem",
+			filesystems:          []string{"rootdisk"},
+			volumes:              []kubevirtapiv1.Volume{volume("rootdisk", kubevirtapiv1.VolumeSource{ContainerDisk: &kubevirtapiv1.ContainerDiskSource{}})},
+			expectedErrorMessage: "filesystem rootdisk: virtiofs only supports persistent_volume_claim, data_volume, config_map, secret, service_account and downward_api volumes",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := kubevirtapiv1.VirtualMachineInstanceSpec{Volumes: tc.volumes}
+			for _, name := range tc.disks {
+				spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, kubevirtapiv1.Disk{Name: name})
+			}
+			for _, name := range tc.filesystems {
+				spec.Domain.Devices.Filesystems = append(spec.Domain.Devices.Filesystems, kubevirtapiv1.Filesystem{Name: name, Virtiofs: &kubevirtapiv1.FilesystemVirtiofs{}})
+			}
+
+			err := ValidateFilesystems(spec)
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestFlattenDevicesWithoutDisks(t *testing.T) {
+	devices := kubevirtapiv1.Devices{
+		Filesystems: []kubevirtapiv1.Filesystem{
+			{Name: "config", Virtiofs: &kubevirtapiv1.FilesystemVirtiofs{}},
+		},
+	}
+
+	flattened := flattenDevices(devices)
+	att := flattened[0].(map[string]interface{})
+	assert.DeepEqual(t, att["disk"], []interface{}{})
+	assert.DeepEqual(t, att["filesystem"], []interface{}{
+		map[string]interface{}{
+			"name":     "config",
+			"virtiofs": []interface{}{map[string]interface{}{}},
+		},
+	})
+
+	expanded, err := expandDevices(flattened)
+	assert.NilError(t, err)
+	assert.Equal(t, len(expanded.Disks), 0)
+	assert.DeepEqual(t, expanded.Filesystems, devices.Filesystems)
+}
@@ -0,0 +1,308 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+var bootloaderKeys = []string{
+	specKey + "domain.0.firmware.0.bootloader.0.bios",
+	specKey + "domain.0.firmware.0.bootloader.0.efi",
+}
+
+func firmwareSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Firmware allows specifying the firmware of the VirtualMachineInstance.",
+		MaxItems:    1,
+		Optional:    true,
+		// KubeVirt fills in the firmware UUID when it is not set.
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"uuid": {
+					Type:        schema.TypeString,
+					Description: "UUID reported by the vmi bios. Defaults to a random generated uid.",
+					Optional:    true,
+					Computed:    true,
+				},
+				"serial": {
+					Type:        schema.TypeString,
+					Description: "The system-serial-number in SMBIOS.",
+					Optional:    true,
+				},
+				"bootloader": {
+					Type:        schema.TypeList,
+					Description: "Settings to control the bootloader that is used. Exactly one of bios or efi may be set.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"bios": {
+								Type:         schema.TypeList,
+								Description:  "If set (default), BIOS will be used.",
+								MaxItems:     1,
+								Optional:     true,
+								ExactlyOneOf: bootloaderKeys,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"use_serial": {
+											Type:        schema.TypeBool,
+											Description: "If set, the BIOS output will be transmitted over serial.",
+											Optional:    true,
+											Default:     false,
+										},
+									},
+								},
+							},
+							"efi": {
+								Type:         schema.TypeList,
+								Description:  "If set, EFI will be used instead of BIOS.",
+								MaxItems:     1,
+								Optional:     true,
+								ExactlyOneOf: bootloaderKeys,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"secure_boot": {
+											Type:        schema.TypeBool,
+											Description: "If set, SecureBoot will be enabled and the OVMF roms will be swapped for SecureBoot-enabled ones. Requires SMM to be enabled. Defaults to true.",
+											Optional:    true,
+											Default:     true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"kernel_boot": {
+					Type:        schema.TypeList,
+					Description: "Settings to set the kernel for booting.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"kernel_args": {
+								Type:        schema.TypeString,
+								Description: "Arguments to be passed to the kernel at boot time.",
+								Optional:    true,
+							},
+							"container": {
+								Type:        schema.TypeList,
+								Description: "Container defines the container that containes kernel artifacts.",
+								MaxItems:    1,
+								Optional:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"image": {
+											Type:        schema.TypeString,
+											Description: "Image that contains initrd / kernel files.",
+											Required:    true,
+										},
+										"image_pull_secret": {
+											Type:        schema.TypeString,
+											Description: "ImagePullSecret is the name of the Docker registry secret required to pull the image.",
+											Optional:    true,
+										},
+										"image_pull_policy": {
+											Type:         schema.TypeString,
+											Description:  "Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always if :latest tag is specified, or IfNotPresent otherwise.",
+											Optional:     true,
+											ValidateFunc: validation.StringInSlice(imagePullPolicies, false),
+										},
+										"kernel_path": {
+											Type:        schema.TypeString,
+											Description: "The fully-qualified path to the kernel image in the host OS.",
+											Optional:    true,
+										},
+										"initrd_path": {
+											Type:        schema.TypeString,
+											Description: "The fully-qualified path to the ramdisk image in the host OS.",
+											Optional:    true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandFirmware(firmware []interface{}) (*kubevirtapiv1.Firmware, error) {
+	if len(firmware) == 0 || firmware[0] == nil {
+		return nil, nil
+	}
+
+	result := &kubevirtapiv1.Firmware{}
+	in := firmware[0].(map[string]interface{})
+
+	if v, ok := in["uuid"].(string); ok {
+		result.UUID = types.UID(v)
+	}
+	if v, ok := in["serial"].(string); ok {
+		result.Serial = v
+	}
+	if v, ok := in["bootloader"].([]interface{}); ok {
+		result.Bootloader = expandBootloader(v)
+	}
+	if v, ok := in["kernel_boot"].([]interface{}); ok {
+		result.KernelBoot = expandKernelBoot(v)
+	}
+
+	return result, nil
+}
+
+func expandBootloader(bootloader []interface{}) *kubevirtapiv1.Bootloader {
+	if len(bootloader) == 0 || bootloader[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Bootloader{}
+	in := bootloader[0].(map[string]interface{})
+
+	if v, ok := in["bios"].([]interface{}); ok && len(v) > 0 {
+		result.BIOS = &kubevirtapiv1.BIOS{}
+		if v[0] != nil {
+			useSerial := v[0].(map[string]interface{})["use_serial"].(bool)
+			result.BIOS.UseSerial = &useSerial
+		}
+	}
+	if v, ok := in["efi"].([]interface{}); ok && len(v) > 0 {
+		result.EFI = &kubevirtapiv1.EFI{}
+		if v[0] != nil {
+			secureBoot := v[0].(map[string]interface{})["secure_boot"].(bool)
+			result.EFI.SecureBoot = &secureBoot
+		}
+	}
+
+	return result
+}
+
+func expandKernelBoot(kernelBoot []interface{}) *kubevirtapiv1.KernelBoot {
+	if len(kernelBoot) == 0 || kernelBoot[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.KernelBoot{}
+	in := kernelBoot[0].(map[string]interface{})
+
+	if v, ok := in["kernel_args"].(string); ok {
+		result.KernelArgs = v
+	}
+	if v, ok := in["container"].([]interface{}); ok {
+		result.Container = expandKernelBootContainer(v)
+	}
+
+	return result
+}
+
+func expandKernelBootContainer(container []interface{}) *kubevirtapiv1.KernelBootContainer {
+	if len(container) == 0 || container[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.KernelBootContainer{}
+	in := container[0].(map[string]interface{})
+
+	if v, ok := in["image"].(string); ok {
+		result.Image = v
+	}
+	if v, ok := in["image_pull_secret"].(string); ok {
+		result.ImagePullSecret = v
+	}
+	if v, ok := in["image_pull_policy"].(string); ok {
+		result.ImagePullPolicy = k8sv1.PullPolicy(v)
+	}
+	if v, ok := in["kernel_path"].(string); ok {
+		result.KernelPath = v
+	}
+	if v, ok := in["initrd_path"].(string); ok {
+		result.InitrdPath = v
+	}
+
+	return result
+}
+
+func flattenFirmware(in kubevirtapiv1.Firmware) []interface{} {
+	att := make(map[string]interface{})
+
+	att["uuid"] = string(in.UUID)
+	att["serial"] = in.Serial
+	if in.Bootloader != nil {
+		att["bootloader"] = flattenBootloader(*in.Bootloader)
+	}
+	if in.KernelBoot != nil {
+		att["kernel_boot"] = flattenKernelBoot(*in.KernelBoot)
+	}
+
+	return []interface{}{att}
+}
+
+func flattenBootloader(in kubevirtapiv1.Bootloader) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.BIOS != nil {
+		att["bios"] = []interface{}{
+			map[string]interface{}{
+				"use_serial": in.BIOS.UseSerial != nil && *in.BIOS.UseSerial,
+			},
+		}
+	}
+	if in.EFI != nil {
+		att["efi"] = []interface{}{
+			map[string]interface{}{
+				// Secure boot is enabled unless explicitly disabled.
+				"secure_boot": in.EFI.SecureBoot == nil || *in.EFI.SecureBoot,
+			},
+		}
+	}
+
+	return []interface{}{att}
+}
+
+func flattenKernelBoot(in kubevirtapiv1.KernelBoot) []interface{} {
+	att := make(map[string]interface{})
+
+	att["kernel_args"] = in.KernelArgs
+	if in.Container != nil {
+		att["container"] = flattenKernelBootContainer(*in.Container)
+	}
+
+	return []interface{}{att}
+}
+
+func flattenKernelBootContainer(in kubevirtapiv1.KernelBootContainer) []interface{} {
+	att := make(map[string]interface{})
+
+	att["image"] = in.Image
+	att["image_pull_secret"] = in.ImagePullSecret
+	att["image_pull_policy"] = string(in.ImagePullPolicy)
+	att["kernel_path"] = in.KernelPath
+	att["initrd_path"] = in.InitrdPath
+
+	return []interface{}{att}
+}
+
+// ValidateFirmware checks that EFI secure boot, which is enabled by default, is only used
+// together with SMM, which KubeVirt rejects otherwise.
+func ValidateFirmware(domain kubevirtapiv1.DomainSpec) error {
+	if domain.Firmware == nil || domain.Firmware.Bootloader == nil || domain.Firmware.Bootloader.EFI == nil {
+		return nil
+	}
+	efi := domain.Firmware.Bootloader.EFI
+	if efi.SecureBoot != nil && !*efi.SecureBoot {
+		return nil
+	}
+	if domain.Features == nil || !featureEnabled(domain.Features.SMM) {
+		return fmt.Errorf("domain.firmware.bootloader.efi.secure_boot requires domain.features.smm to be enabled")
+	}
+	return nil
+}
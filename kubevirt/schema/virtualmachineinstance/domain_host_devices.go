@@ -0,0 +1,210 @@
+package virtualmachineinstance
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func gpusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "GPUs to pass through to the vmi. Requires the GPU feature gate.",
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Description: "Name of the GPU device in the vmi.",
+					Required:    true,
+				},
+				"device_name": deviceNameSchema(),
+				"virtual_gpu_options": {
+					Type:        schema.TypeList,
+					Description: "Options of a mediated (vGPU) device.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"display": {
+								Type:        schema.TypeList,
+								Description: "Display options of the vGPU.",
+								MaxItems:    1,
+								Optional:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"enabled": {
+											Type:        schema.TypeBool,
+											Description: "Enabled determines if a display adapter backed by the vGPU should be enabled or disabled on the guest. Defaults to true.",
+											Optional:    true,
+											Default:     true,
+										},
+										"ram_fb": featureStateSchema("Enables a boot framebuffer, until the guest OS loads a real GPU driver."),
+									},
+								},
+							},
+						},
+					},
+				},
+				"tag": deviceTagSchema(),
+			},
+		},
+	}
+}
+
+func hostDevicesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Host devices to pass through to the vmi. Requires the HostDevices feature gate.",
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Description: "Name of the host device in the vmi.",
+					Required:    true,
+				},
+				"device_name": deviceNameSchema(),
+				"tag":         deviceTagSchema(),
+			},
+		},
+	}
+}
+
+func deviceNameSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "DeviceName is the resource name of the device, as permitted in the cluster configuration, e.g. nvidia.com/GP102GL_Tesla_P40.",
+		Required:     true,
+		ValidateFunc: utils.ValidateDeviceResourceName,
+	}
+}
+
+func deviceTagSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "If specified, the device address and its tag will be provided to the guest via config drive.",
+		Optional:    true,
+	}
+}
+
+func expandGPUs(gpus []interface{}) []kubevirtapiv1.GPU {
+	result := make([]kubevirtapiv1.GPU, len(gpus))
+
+	for i, gpu := range gpus {
+		in := gpu.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["device_name"].(string); ok {
+			result[i].DeviceName = v
+		}
+		if v, ok := in["virtual_gpu_options"].([]interface{}); ok {
+			result[i].VirtualGPUOptions = expandVGPUOptions(v)
+		}
+		if v, ok := in["tag"].(string); ok {
+			result[i].Tag = v
+		}
+	}
+
+	return result
+}
+
+func expandVGPUOptions(options []interface{}) *kubevirtapiv1.VGPUOptions {
+	if len(options) == 0 {
+		return nil
+	}
+
+	result := &kubevirtapiv1.VGPUOptions{}
+	if options[0] == nil {
+		return result
+	}
+	in := options[0].(map[string]interface{})
+
+	if v, ok := in["display"].([]interface{}); ok && len(v) > 0 {
+		result.Display = &kubevirtapiv1.VGPUDisplayOptions{}
+		if v[0] != nil {
+			display := v[0].(map[string]interface{})
+			if enabled, ok := display["enabled"].(bool); ok {
+				result.Display.Enabled = &enabled
+			}
+			if ramFB, ok := display["ram_fb"].([]interface{}); ok {
+				result.Display.RamFB = expandFeatureState(ramFB)
+			}
+		}
+	}
+
+	return result
+}
+
+func expandHostDevices(hostDevices []interface{}) []kubevirtapiv1.HostDevice {
+	result := make([]kubevirtapiv1.HostDevice, len(hostDevices))
+
+	for i, hostDevice := range hostDevices {
+		in := hostDevice.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["device_name"].(string); ok {
+			result[i].DeviceName = v
+		}
+		if v, ok := in["tag"].(string); ok {
+			result[i].Tag = v
+		}
+	}
+
+	return result
+}
+
+func flattenGPUs(in []kubevirtapiv1.GPU) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		c["device_name"] = v.DeviceName
+		if v.VirtualGPUOptions != nil {
+			c["virtual_gpu_options"] = flattenVGPUOptions(*v.VirtualGPUOptions)
+		}
+		c["tag"] = v.Tag
+
+		att[i] = c
+	}
+
+	return att
+}
+
+func flattenVGPUOptions(in kubevirtapiv1.VGPUOptions) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.Display != nil {
+		display := map[string]interface{}{
+			"enabled": in.Display.Enabled == nil || *in.Display.Enabled,
+		}
+		if in.Display.RamFB != nil {
+			display["ram_fb"] = flattenFeatureState(*in.Display.RamFB)
+		}
+		att["display"] = []interface{}{display}
+	}
+
+	return []interface{}{att}
+}
+
+func flattenHostDevices(in []kubevirtapiv1.HostDevice) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		c["device_name"] = v.DeviceName
+		c["tag"] = v.Tag
+
+		att[i] = c
+	}
+
+	return att
+}
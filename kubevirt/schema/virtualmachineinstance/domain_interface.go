@@ -0,0 +1,372 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func interfacesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Interfaces describe network interfaces which are added to the vmi.",
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Description: "Logical name of the interface as well as a reference to the associated networks.",
+					Required:    true,
+				},
+				"interface_binding_method": {
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{
+						"InterfaceBridge",
+						"InterfaceSlirp",
+						"InterfaceMasquerade",
+						"InterfaceSRIOV",
+						"InterfaceMacvtap",
+						"InterfacePasst",
+					}, false),
+					Description: "Represents the method which will be used to connect the interface to the guest.",
+					Required:    true,
+				},
+				"model": {
+					Type:        schema.TypeString,
+					Description: "Interface model. One of: e1000, e1000e, ne2k_pci, pcnet, rtl8139, virtio. Defaults to virtio.",
+					Optional:    true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"e1000",
+						"e1000e",
+						"ne2k_pci",
+						"pcnet",
+						"rtl8139",
+						"virtio",
+					}, false),
+				},
+				"mac_address": {
+					Type:         schema.TypeString,
+					Description:  "Interface MAC address. For example: de:ad:00:00:be:af or DE-AD-00-00-BE-AF.",
+					Optional:     true,
+					ValidateFunc: validation.IsMACAddress,
+				},
+				"ports": {
+					Type:        schema.TypeList,
+					Description: "List of ports to be forwarded to the virtual machine. Only supported by the InterfaceMasquerade, InterfaceSlirp and InterfacePasst binding methods.",
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:        schema.TypeString,
+								Description: "If specified, this must be an IANA_SVC_NAME and unique within the pod. Each named port in a pod must have a unique name.",
+								Optional:    true,
+							},
+							"protocol": {
+								Type:         schema.TypeString,
+								Description:  "Protocol for port. Must be UDP or TCP. Defaults to \"TCP\".",
+								Optional:     true,
+								ValidateFunc: validation.StringInSlice([]string{"TCP", "UDP"}, false),
+							},
+							"port": {
+								Type:         schema.TypeInt,
+								Description:  "Number of port to expose for the virtual machine. This must be a valid port number, 0 < x < 65536.",
+								Required:     true,
+								ValidateFunc: validation.IsPortNumber,
+							},
+						},
+					},
+				},
+				"pci_address": {
+					Type:        schema.TypeString,
+					Description: "If specified, the virtual network interface will be placed on the guests pci address with the specified PCI address. For example: 0000:81:01.10",
+					Optional:    true,
+				},
+				"boot_order": {
+					Type:         schema.TypeInt,
+					Description:  "BootOrder is an integer value > 0, used to determine ordering of boot devices. Lower values take precedence. Each interface or disk that has a boot order must have a unique value. Interfaces without a boot order are not tried.",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"dhcp_options": {
+					Type:        schema.TypeList,
+					Description: "If specified the network interface will pass additional DHCP options to the VMI.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"boot_file_name": {
+								Type:        schema.TypeString,
+								Description: "If specified will pass option 67 to interface's DHCP server.",
+								Optional:    true,
+							},
+							"tftp_server_name": {
+								Type:        schema.TypeString,
+								Description: "If specified will pass option 66 to interface's DHCP server.",
+								Optional:    true,
+							},
+							"ntp_servers": {
+								Type:        schema.TypeList,
+								Description: "If specified will pass the configured NTP server to the VM via DHCP option 042.",
+								Optional:    true,
+								Elem: &schema.Schema{
+									Type: schema.TypeString,
+								},
+							},
+							"private_options": {
+								Type:        schema.TypeList,
+								Description: "If specified will pass extra DHCP options for private use, range: 224-254.",
+								Optional:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"option": {
+											Type:         schema.TypeInt,
+											Description:  "Option is an Integer value from 224-254.",
+											Required:     true,
+											ValidateFunc: validation.IntBetween(224, 254),
+										},
+										"value": {
+											Type:        schema.TypeString,
+											Description: "Value is a String value for the Option provided.",
+											Required:    true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"tag": {
+					Type:        schema.TypeString,
+					Description: "If specified, the virtual network interface address and its tag will be provided to the guest via config drive.",
+					Optional:    true,
+				},
+				"acpi_index": {
+					Type:         schema.TypeInt,
+					Description:  "If specified, the ACPI index is used to provide network interface device naming, that is stable across changes in PCI addresses assigned to the device. This value is required to be unique across all devices and be between 1 and (16*1024-1).",
+					Optional:     true,
+					ValidateFunc: validation.IntBetween(1, 16*1024-1),
+				},
+			},
+		},
+	}
+}
+
+func expandInterfaces(interfaces []interface{}) []kubevirtapiv1.Interface {
+	result := make([]kubevirtapiv1.Interface, len(interfaces))
+
+	if len(interfaces) == 0 || interfaces[0] == nil {
+		return result
+	}
+
+	for i, condition := range interfaces {
+		in := condition.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["interface_binding_method"].(string); ok {
+			result[i].InterfaceBindingMethod = expandInterfaceBindingMethod(v)
+		}
+		if v, ok := in["model"].(string); ok {
+			result[i].Model = v
+		}
+		if v, ok := in["mac_address"].(string); ok {
+			result[i].MacAddress = v
+		}
+		if v, ok := in["ports"].([]interface{}); ok {
+			result[i].Ports = expandPorts(v)
+		}
+		if v, ok := in["pci_address"].(string); ok {
+			result[i].PciAddress = v
+		}
+		if v, ok := in["boot_order"].(int); ok && v > 0 {
+			bootOrder := uint(v)
+			result[i].BootOrder = &bootOrder
+		}
+		if v, ok := in["dhcp_options"].([]interface{}); ok {
+			result[i].DHCPOptions = expandDHCPOptions(v)
+		}
+		if v, ok := in["tag"].(string); ok {
+			result[i].Tag = v
+		}
+		if v, ok := in["acpi_index"].(int); ok {
+			result[i].ACPIIndex = v
+		}
+	}
+
+	return result
+}
+
+func expandInterfaceBindingMethod(interfaceBindingMethod string) kubevirtapiv1.InterfaceBindingMethod {
+	result := kubevirtapiv1.InterfaceBindingMethod{}
+
+	switch interfaceBindingMethod {
+	case "InterfaceBridge":
+		result.Bridge = &kubevirtapiv1.InterfaceBridge{}
+	case "InterfaceSlirp":
+		result.Slirp = &kubevirtapiv1.InterfaceSlirp{}
+	case "InterfaceMasquerade":
+		result.Masquerade = &kubevirtapiv1.InterfaceMasquerade{}
+	case "InterfaceSRIOV":
+		result.SRIOV = &kubevirtapiv1.InterfaceSRIOV{}
+	case "InterfaceMacvtap":
+		result.Macvtap = &kubevirtapiv1.InterfaceMacvtap{}
+	case "InterfacePasst":
+		result.Passt = &kubevirtapiv1.InterfacePasst{}
+	}
+
+	return result
+}
+
+func expandPorts(ports []interface{}) []kubevirtapiv1.Port {
+	result := make([]kubevirtapiv1.Port, len(ports))
+
+	for i, port := range ports {
+		in := port.(map[string]interface{})
+
+		if v, ok := in["name"].(string); ok {
+			result[i].Name = v
+		}
+		if v, ok := in["protocol"].(string); ok {
+			result[i].Protocol = v
+		}
+		if v, ok := in["port"].(int); ok {
+			result[i].Port = int32(v)
+		}
+	}
+
+	return result
+}
+
+func expandDHCPOptions(dhcpOptions []interface{}) *kubevirtapiv1.DHCPOptions {
+	if len(dhcpOptions) == 0 || dhcpOptions[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.DHCPOptions{}
+	in := dhcpOptions[0].(map[string]interface{})
+
+	if v, ok := in["boot_file_name"].(string); ok {
+		result.BootFileName = v
+	}
+	if v, ok := in["tftp_server_name"].(string); ok {
+		result.TFTPServerName = v
+	}
+	if v, ok := in["ntp_servers"].([]interface{}); ok && len(v) > 0 {
+		result.NTPServers = make([]string, len(v))
+		for i, server := range v {
+			result.NTPServers[i] = server.(string)
+		}
+	}
+	if v, ok := in["private_options"].([]interface{}); ok && len(v) > 0 {
+		result.PrivateOptions = make([]kubevirtapiv1.DHCPPrivateOptions, len(v))
+		for i, option := range v {
+			o := option.(map[string]interface{})
+			result.PrivateOptions[i].Option = o["option"].(int)
+			result.PrivateOptions[i].Value = o["value"].(string)
+		}
+	}
+
+	return result
+}
+
+func flattenInterfaces(in []kubevirtapiv1.Interface) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		c["interface_binding_method"] = flattenInterfaceBindingMethod(v.InterfaceBindingMethod)
+		c["model"] = v.Model
+		c["mac_address"] = v.MacAddress
+		c["ports"] = flattenPorts(v.Ports)
+		c["pci_address"] = v.PciAddress
+		if v.BootOrder != nil {
+			c["boot_order"] = int(*v.BootOrder)
+		}
+		if v.DHCPOptions != nil {
+			c["dhcp_options"] = flattenDHCPOptions(*v.DHCPOptions)
+		}
+		c["tag"] = v.Tag
+		c["acpi_index"] = v.ACPIIndex
+
+		att[i] = c
+	}
+
+	return att
+}
+
+func flattenInterfaceBindingMethod(in kubevirtapiv1.InterfaceBindingMethod) string {
+	if in.Bridge != nil {
+		return "InterfaceBridge"
+	}
+	if in.Slirp != nil {
+		return "InterfaceSlirp"
+	}
+	if in.Masquerade != nil {
+		return "InterfaceMasquerade"
+	}
+	if in.SRIOV != nil {
+		return "InterfaceSRIOV"
+	}
+	if in.Macvtap != nil {
+		return "InterfaceMacvtap"
+	}
+	if in.Passt != nil {
+		return "InterfacePasst"
+	}
+
+	return ""
+}
+
+func flattenPorts(in []kubevirtapiv1.Port) []interface{} {
+	att := make([]interface{}, len(in))
+
+	for i, v := range in {
+		c := make(map[string]interface{})
+
+		c["name"] = v.Name
+		c["protocol"] = v.Protocol
+		c["port"] = int(v.Port)
+
+		att[i] = c
+	}
+
+	return att
+}
+
+func flattenDHCPOptions(in kubevirtapiv1.DHCPOptions) []interface{} {
+	att := make(map[string]interface{})
+
+	att["boot_file_name"] = in.BootFileName
+	att["tftp_server_name"] = in.TFTPServerName
+	ntpServers := make([]interface{}, len(in.NTPServers))
+	for i, server := range in.NTPServers {
+		ntpServers[i] = server
+	}
+	att["ntp_servers"] = ntpServers
+	privateOptions := make([]interface{}, len(in.PrivateOptions))
+	for i, option := range in.PrivateOptions {
+		privateOptions[i] = map[string]interface{}{
+			"option": option.Option,
+			"value":  option.Value,
+		}
+	}
+	att["private_options"] = privateOptions
+
+	return []interface{}{att}
+}
+
+// ValidateInterfaces checks that ports are only forwarded by interfaces whose binding method
+// supports it. Interfaces are list elements, which schema constraints cannot reference.
+func ValidateInterfaces(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+	for _, iface := range spec.Domain.Devices.Interfaces {
+		if len(iface.Ports) > 0 && iface.Masquerade == nil && iface.Slirp == nil && iface.Passt == nil {
+			return fmt.Errorf("interface %s: ports can only be forwarded with the InterfaceMasquerade, InterfaceSlirp or InterfacePasst binding method", iface.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateInterfaces(t *testing.T) {
+	ports := []kubevirtapiv1.Port{{Name: "ssh", Port: 22}}
+
+	cases := []struct {
+		name                 string
+		iface                kubevirtapiv1.Interface
+		expectedErrorMessage string
+	}{
+		{
+			name: "masquerade with ports",
+			iface: kubevirtapiv1.Interface{
+				Name:                   "main",
+				InterfaceBindingMethod: expandInterfaceBindingMethod("InterfaceMasquerade"),
+				Ports:                  ports,
+			},
+		},
+		{
+			name: "bridge without ports",
+			iface: kubevirtapiv1.Interface{
+				Name:                   "main",
+				InterfaceBindingMethod: expandInterfaceBindingMethod("InterfaceBridge"),
+			},
+		},
+		{
+			name: "bridge with ports",
+			iface: kubevirtapiv1.Interface{
+				Name:                   "main",
+				InterfaceBindingMethod: expandInterfaceBindingMethod("InterfaceBridge"),
+				Ports:                  ports,
+			},
+			expectedErrorMessage: "interface main: ports can only be forwarded with the InterfaceMasquerade, InterfaceSlirp or InterfacePasst binding method",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := kubevirtapiv1.VirtualMachineInstanceSpec{}
+			spec.Domain.Devices.Interfaces = []kubevirtapiv1.Interface{tc.iface}
+
+			err := ValidateInterfaces(spec)
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
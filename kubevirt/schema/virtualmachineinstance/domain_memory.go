@@ -0,0 +1,123 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+func memorySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Memory allows specifying the VMI memory features. MaxGuest, used for memory hotplug, is not available in the KubeVirt API version this provider is built against.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"guest": {
+					Type:         schema.TypeString,
+					Description:  "Guest allows to specifying the amount of memory which is visible inside the Guest OS. The Guest must lie between Requests and Limits from the resources section. Defaults to the requested memory in the resources section if not specified.",
+					Optional:     true,
+					ValidateFunc: utils.ValidateResourceQuantity,
+				},
+				"hugepages": {
+					Type:        schema.TypeList,
+					Description: "Hugepages allow to use hugepages for the VirtualMachineInstance instead of regular memory.",
+					MaxItems:    1,
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"page_size": {
+								Type:         schema.TypeString,
+								Description:  "PageSize specifies the hugepage size, valid values depend on the architecture, e.g. 2Mi and 1Gi for x86_64, or 64Ki, 2Mi, 32Mi, 512Mi and 1Gi for arm64.",
+								Required:     true,
+								ValidateFunc: utils.ValidateResourceQuantity,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandMemory(memory []interface{}) (*kubevirtapiv1.Memory, error) {
+	if len(memory) == 0 || memory[0] == nil {
+		return nil, nil
+	}
+
+	result := &kubevirtapiv1.Memory{}
+	in := memory[0].(map[string]interface{})
+
+	if v, ok := in["guest"].(string); ok && v != "" {
+		guest, err := resource.ParseQuantity(v)
+		if err != nil {
+			return result, err
+		}
+		result.Guest = &guest
+	}
+	if v, ok := in["hugepages"].([]interface{}); ok {
+		result.Hugepages = expandHugepages(v)
+	}
+
+	return result, nil
+}
+
+func expandHugepages(hugepages []interface{}) *kubevirtapiv1.Hugepages {
+	if len(hugepages) == 0 || hugepages[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.Hugepages{}
+	in := hugepages[0].(map[string]interface{})
+
+	if v, ok := in["page_size"].(string); ok {
+		result.PageSize = v
+	}
+
+	return result
+}
+
+func flattenMemory(in kubevirtapiv1.Memory) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.Guest != nil {
+		att["guest"] = in.Guest.String()
+	}
+	if in.Hugepages != nil {
+		att["hugepages"] = flattenHugepages(*in.Hugepages)
+	}
+
+	return []interface{}{att}
+}
+
+func flattenHugepages(in kubevirtapiv1.Hugepages) []interface{} {
+	att := make(map[string]interface{})
+
+	att["page_size"] = in.PageSize
+
+	return []interface{}{att}
+}
+
+// ValidateMemory checks the guest memory against the memory resources of the domain. It returns
+// an error when the guest memory exceeds the limit, which KubeVirt rejects, and a warning when it
+// exceeds the request without overcommitting the guest overhead. The warning is reported when the
+// virtual machine is created, as CustomizeDiff cannot return warnings.
+func ValidateMemory(domain kubevirtapiv1.DomainSpec) (string, error) {
+	if domain.Memory == nil || domain.Memory.Guest == nil {
+		return "", nil
+	}
+	guest := domain.Memory.Guest
+
+	if limit, ok := domain.Resources.Limits[k8sv1.ResourceMemory]; ok && guest.Cmp(limit) > 0 {
+		return "", fmt.Errorf("domain.memory.guest (%s) must not exceed the memory limit (%s)", guest.String(), limit.String())
+	}
+	if request, ok := domain.Resources.Requests[k8sv1.ResourceMemory]; ok && guest.Cmp(request) > 0 && !domain.Resources.OvercommitGuestOverhead {
+		return fmt.Sprintf("domain.memory.guest (%s) exceeds the memory request (%s), the virtual machine may be scheduled on a node without enough memory; set over_commit_guest_overhead if this is intended", guest.String(), request.String()), nil
+	}
+	return "", nil
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
 )
@@ -36,93 +35,30 @@ func domainSpecFields() map[string]*schema.Schema {
 				},
 			},
 		},
-		"devices": {
+		"cpu":    cpuSchema(),
+		"memory": memorySchema(),
+		"machine": {
 			Type:        schema.TypeList,
-			Description: "Devices allows adding disks, network interfaces, ...",
+			Description: "Machine type.",
 			MaxItems:    1,
-			Required:    true,
+			Optional:    true,
+			// KubeVirt sets the cluster's default machine type when it is not set.
+			Computed: true,
 			Elem: &schema.Resource{
 				Schema: map[string]*schema.Schema{
-					"disk": {
-						Type:        schema.TypeList,
-						Description: "Disks describes disks, cdroms, floppy and luns which are connected to the vmi.",
-						Required:    true,
-						Elem: &schema.Resource{
-							Schema: map[string]*schema.Schema{
-								"name": {
-									Type:        schema.TypeString,
-									Description: "Name is the device name",
-									Required:    true,
-								},
-								"disk_device": {
-									Type:        schema.TypeList,
-									Description: "DiskDevice specifies as which device the disk should be added to the guest.",
-									Required:    true,
-									Elem: &schema.Resource{
-										Schema: map[string]*schema.Schema{
-											"disk": {
-												Type:        schema.TypeList,
-												Description: "Attach a volume as a disk to the vmi.",
-												Optional:    true,
-												Elem: &schema.Resource{
-													Schema: map[string]*schema.Schema{
-														"bus": {
-															Type:        schema.TypeString,
-															Description: "Bus indicates the type of disk device to emulate.",
-															Required:    true,
-														},
-														"read_only": {
-															Type:        schema.TypeBool,
-															Description: "ReadOnly. Defaults to false.",
-															Optional:    true,
-														},
-														"pci_address": {
-															Type:        schema.TypeString,
-															Description: "If specified, the virtual disk will be placed on the guests pci address with the specifed PCI address. For example: 0000:81:01.10",
-															Optional:    true,
-														},
-													},
-												},
-											},
-										},
-									},
-								},
-								"serial": {
-									Type:        schema.TypeString,
-									Description: "Serial provides the ability to specify a serial number for the disk device.",
-									Optional:    true,
-								},
-							},
-						},
-					},
-					"interface": {
-						Type:        schema.TypeList,
-						Description: "Interfaces describe network interfaces which are added to the vmi.",
+					"type": {
+						Type:        schema.TypeString,
+						Description: "QEMU machine type is the actual chipset of the VirtualMachineInstance.",
 						Optional:    true,
-						Elem: &schema.Resource{
-							Schema: map[string]*schema.Schema{
-								"name": {
-									Type:        schema.TypeString,
-									Description: "Logical name of the interface as well as a reference to the associated networks.",
-									Required:    true,
-								},
-								"interface_binding_method": {
-									Type: schema.TypeString,
-									ValidateFunc: validation.StringInSlice([]string{
-										"InterfaceBridge",
-										"InterfaceSlirp",
-										"InterfaceMasquerade",
-										"InterfaceSRIOV",
-									}, false),
-									Description: "Represents the method which will be used to connect the interface to the guest.",
-									Required:    true,
-								},
-							},
-						},
+						Computed:    true,
 					},
 				},
 			},
 		},
+		"firmware": firmwareSchema(),
+		"clock":    clockSchema(),
+		"features": featuresSchema(),
+		"devices":  devicesSchema(),
 	}
 }
 
@@ -158,6 +94,32 @@ func expandDomainSpec(domainSpec []interface{}) (kubevirtapiv1.DomainSpec, error
 		}
 		result.Resources = resources
 	}
+	if v, ok := in["cpu"].([]interface{}); ok {
+		result.CPU = expandCPU(v)
+	}
+	if v, ok := in["memory"].([]interface{}); ok {
+		memory, err := expandMemory(v)
+		if err != nil {
+			return result, err
+		}
+		result.Memory = memory
+	}
+	if v, ok := in["machine"].([]interface{}); ok {
+		result.Machine = expandMachine(v)
+	}
+	if v, ok := in["firmware"].([]interface{}); ok {
+		firmware, err := expandFirmware(v)
+		if err != nil {
+			return result, err
+		}
+		result.Firmware = firmware
+	}
+	if v, ok := in["clock"].([]interface{}); ok {
+		result.Clock = expandClock(v)
+	}
+	if v, ok := in["features"].([]interface{}); ok {
+		result.Features = expandFeatures(v)
+	}
 	if v, ok := in["devices"].([]interface{}); ok {
 		devices, err := expandDevices(v)
 		if err != nil {
@@ -199,129 +161,43 @@ func expandResources(resources []interface{}) (kubevirtapiv1.ResourceRequirement
 	return result, nil
 }
 
-func expandDevices(devices []interface{}) (kubevirtapiv1.Devices, error) {
-	result := kubevirtapiv1.Devices{}
-
-	if len(devices) == 0 || devices[0] == nil {
-		return result, nil
-	}
-
-	in := devices[0].(map[string]interface{})
-
-	if v, ok := in["disk"].([]interface{}); ok {
-		result.Disks = expandDisks(v)
-	}
-	if v, ok := in["interface"].([]interface{}); ok {
-		result.Interfaces = expandInterfaces(v)
-	}
-
-	return result, nil
-}
-
-func expandDisks(disks []interface{}) []kubevirtapiv1.Disk {
-	result := make([]kubevirtapiv1.Disk, len(disks))
-
-	if len(disks) == 0 || disks[0] == nil {
-		return result
-	}
-
-	for i, condition := range disks {
-		in := condition.(map[string]interface{})
-
-		if v, ok := in["name"].(string); ok {
-			result[i].Name = v
-		}
-		if v, ok := in["disk_device"].([]interface{}); ok {
-			result[i].DiskDevice = expandDiskDevice(v)
-		}
-		if v, ok := in["serial"].(string); ok {
-			result[i].Serial = v
-		}
-	}
-
-	return result
-}
-
-func expandDiskDevice(diskDevice []interface{}) kubevirtapiv1.DiskDevice {
-	result := kubevirtapiv1.DiskDevice{}
-
-	if len(diskDevice) == 0 || diskDevice[0] == nil {
-		return result
+func expandMachine(machine []interface{}) *kubevirtapiv1.Machine {
+	if len(machine) == 0 || machine[0] == nil {
+		return nil
 	}
 
-	in := diskDevice[0].(map[string]interface{})
+	result := &kubevirtapiv1.Machine{}
+	in := machine[0].(map[string]interface{})
 
-	if v, ok := in["disk"].([]interface{}); ok {
-		result.Disk = expandDiskTarget(v)
+	if v, ok := in["type"].(string); ok {
+		result.Type = v
 	}
 
 	return result
 }
 
-func expandDiskTarget(disk []interface{}) *kubevirtapiv1.DiskTarget {
-	if len(disk) == 0 || disk[0] == nil {
-		return nil
-	}
-
-	result := &kubevirtapiv1.DiskTarget{}
-
-	in := disk[0].(map[string]interface{})
+func flattenDomainSpec(in kubevirtapiv1.DomainSpec) []interface{} {
+	att := make(map[string]interface{})
 
-	if v, ok := in["bus"].(string); ok {
-		result.Bus = kubevirtapiv1.DiskBus(v)
+	att["resources"] = flattenResources(in.Resources)
+	if in.CPU != nil {
+		att["cpu"] = flattenCPU(*in.CPU)
 	}
-	if v, ok := in["read_only"].(bool); ok {
-		result.ReadOnly = v
+	if in.Memory != nil {
+		att["memory"] = flattenMemory(*in.Memory)
 	}
-	if v, ok := in["pci_address"].(string); ok {
-		result.PciAddress = v
+	if in.Machine != nil {
+		att["machine"] = flattenMachine(*in.Machine)
 	}
-
-	return result
-}
-
-func expandInterfaces(interfaces []interface{}) []kubevirtapiv1.Interface {
-	result := make([]kubevirtapiv1.Interface, len(interfaces))
-
-	if len(interfaces) == 0 || interfaces[0] == nil {
-		return result
+	if in.Firmware != nil {
+		att["firmware"] = flattenFirmware(*in.Firmware)
 	}
-
-	for i, condition := range interfaces {
-		in := condition.(map[string]interface{})
-
-		if v, ok := in["name"].(string); ok {
-			result[i].Name = v
-		}
-		if v, ok := in["interface_binding_method"].(string); ok {
-			result[i].InterfaceBindingMethod = expandInterfaceBindingMethod(v)
-		}
+	if in.Clock != nil {
+		att["clock"] = flattenClock(*in.Clock)
 	}
-
-	return result
-}
-
-func expandInterfaceBindingMethod(interfaceBindingMethod string) kubevirtapiv1.InterfaceBindingMethod {
-	result := kubevirtapiv1.InterfaceBindingMethod{}
-
-	switch interfaceBindingMethod {
-	case "InterfaceBridge":
-		result.Bridge = &kubevirtapiv1.InterfaceBridge{}
-	case "InterfaceSlirp":
-		result.Slirp = &kubevirtapiv1.InterfaceSlirp{}
-	case "InterfaceMasquerade":
-		result.Masquerade = &kubevirtapiv1.InterfaceMasquerade{}
-	case "InterfaceSRIOV":
-		result.SRIOV = &kubevirtapiv1.InterfaceSRIOV{}
+	if in.Features != nil {
+		att["features"] = flattenFeatures(*in.Features)
 	}
-
-	return result
-}
-
-func flattenDomainSpec(in kubevirtapiv1.DomainSpec) []interface{} {
-	att := make(map[string]interface{})
-
-	att["resources"] = flattenResources(in.Resources)
 	att["devices"] = flattenDevices(in.Devices)
 
 	return []interface{}{att}
@@ -337,79 +213,10 @@ func flattenResources(in kubevirtapiv1.ResourceRequirements) []interface{} {
 	return []interface{}{att}
 }
 
-func flattenDevices(in kubevirtapiv1.Devices) []interface{} {
+func flattenMachine(in kubevirtapiv1.Machine) []interface{} {
 	att := make(map[string]interface{})
 
-	att["disk"] = flattenDisks(in.Disks)
-	att["interface"] = flattenInterfaces(in.Interfaces)
+	att["type"] = in.Type
 
 	return []interface{}{att}
 }
-
-func flattenDisks(in []kubevirtapiv1.Disk) []interface{} {
-	att := make([]interface{}, len(in))
-
-	for i, v := range in {
-		c := make(map[string]interface{})
-
-		c["name"] = v.Name
-		c["disk_device"] = flattenDiskDevice(v.DiskDevice)
-		c["serial"] = v.Serial
-
-		att[i] = c
-	}
-
-	return att
-}
-
-func flattenDiskDevice(in kubevirtapiv1.DiskDevice) []interface{} {
-	att := make(map[string]interface{})
-
-	if in.Disk != nil {
-		att["disk"] = flattenDiskTarget(*in.Disk)
-	}
-
-	return []interface{}{att}
-}
-
-func flattenDiskTarget(in kubevirtapiv1.DiskTarget) []interface{} {
-	att := make(map[string]interface{})
-
-	att["bus"] = in.Bus
-	att["read_only"] = in.ReadOnly
-	att["pci_address"] = in.PciAddress
-
-	return []interface{}{att}
-}
-
-func flattenInterfaces(in []kubevirtapiv1.Interface) []interface{} {
-	att := make([]interface{}, len(in))
-
-	for i, v := range in {
-		c := make(map[string]interface{})
-
-		c["name"] = v.Name
-		c["interface_binding_method"] = flattenInterfaceBindingMethod(v.InterfaceBindingMethod)
-
-		att[i] = c
-	}
-
-	return att
-}
-
-func flattenInterfaceBindingMethod(in kubevirtapiv1.InterfaceBindingMethod) string {
-	if in.Bridge != nil {
-		return "InterfaceBridge"
-	}
-	if in.Slirp != nil {
-		return "InterfaceSlirp"
-	}
-	if in.Masquerade != nil {
-		return "InterfaceMasquerade"
-	}
-	if in.SRIOV != nil {
-		return "InterfaceSRIOV"
-	}
-
-	return ""
-}
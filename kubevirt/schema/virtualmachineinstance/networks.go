@@ -2,6 +2,7 @@ package virtualmachineinstance
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
@@ -70,7 +71,6 @@ func networksSchema() *schema.Schema {
 
 		Description: fmt.Sprintf("List of networks that can be attached to a vm's virtual interface."),
 		Optional:    true,
-		MaxItems:    1,
 		Elem: &schema.Resource{
 			Schema: fields,
 		},
@@ -119,12 +119,15 @@ func expandNetworkSource(networkSource []interface{}) kubevirtapiv1.NetworkSourc
 }
 
 func expandPodNetwork(pod []interface{}) *kubevirtapiv1.PodNetwork {
-	result := &kubevirtapiv1.PodNetwork{}
+	// An empty block selects the pod network with the default CIDR.
+	if len(pod) == 0 {
+		return nil
+	}
 
-	if len(pod) == 0 || pod[0] == nil {
+	result := &kubevirtapiv1.PodNetwork{}
+	if pod[0] == nil {
 		return result
 	}
-
 	in := pod[0].(map[string]interface{})
 
 	if v, ok := in["vm_network_cidr"].(string); ok {
@@ -196,3 +199,81 @@ func flattenMultusNetwork(in kubevirtapiv1.MultusNetwork) []interface{} {
 
 	return []interface{}{att}
 }
+
+// bindingNetworkSources lists the network sources each interface binding method supports.
+var bindingNetworkSources = map[string][]string{
+	"InterfaceBridge":     {"pod", "multus"},
+	"InterfaceSlirp":      {"pod"},
+	"InterfaceMasquerade": {"pod"},
+	"InterfaceSRIOV":      {"multus"},
+	"InterfaceMacvtap":    {"multus"},
+	"InterfacePasst":      {"pod"},
+}
+
+// ValidateNetworks checks that the interfaces and networks of the vmi match each other by name,
+// that at most one network is the default one and that each interface binding method supports
+// the source of its network.
+func ValidateNetworks(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+	networks := make(map[string]kubevirtapiv1.Network, len(spec.Networks))
+	var defaults []string
+	for _, network := range spec.Networks {
+		if _, ok := networks[network.Name]; ok {
+			return fmt.Errorf("network %s is defined more than once", network.Name)
+		}
+		networks[network.Name] = network
+
+		source := networkSourceName(network.NetworkSource)
+		if source == "" {
+			return fmt.Errorf("network %s must have exactly one of pod or multus set", network.Name)
+		}
+		if source == "pod" || network.Multus.Default {
+			defaults = append(defaults, network.Name)
+		}
+	}
+	if len(defaults) > 1 {
+		return fmt.Errorf("only one network can be the default one, got %s", strings.Join(defaults, ", "))
+	}
+
+	interfaces := make(map[string]bool, len(spec.Domain.Devices.Interfaces))
+	for _, iface := range spec.Domain.Devices.Interfaces {
+		interfaces[iface.Name] = true
+
+		network, ok := networks[iface.Name]
+		if !ok {
+			return fmt.Errorf("interface %s has no matching network", iface.Name)
+		}
+		binding := flattenInterfaceBindingMethod(iface.InterfaceBindingMethod)
+		source := networkSourceName(network.NetworkSource)
+		if supported, ok := bindingNetworkSources[binding]; ok && !stringInSlice(source, supported) {
+			return fmt.Errorf("interface %s: binding method %s is not supported on %s network %s", iface.Name, binding, source, network.Name)
+		}
+	}
+	for _, network := range spec.Networks {
+		if !interfaces[network.Name] {
+			return fmt.Errorf("network %s has no matching interface", network.Name)
+		}
+	}
+
+	return nil
+}
+
+// networkSourceName returns the kind of the network source, or an empty string unless exactly
+// one is set.
+func networkSourceName(in kubevirtapiv1.NetworkSource) string {
+	switch {
+	case in.Pod != nil && in.Multus == nil:
+		return "pod"
+	case in.Multus != nil && in.Pod == nil:
+		return "multus"
+	}
+	return ""
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
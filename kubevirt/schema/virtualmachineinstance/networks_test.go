@@ -0,0 +1,93 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateNetworks(t *testing.T) {
+	podNetwork := func(name string) kubevirtapiv1.Network {
+		return kubevirtapiv1.Network{
+			Name:          name,
+			NetworkSource: kubevirtapiv1.NetworkSource{Pod: &kubevirtapiv1.PodNetwork{}},
+		}
+	}
+	multusNetwork := func(name string, isDefault bool) kubevirtapiv1.Network {
+		return kubevirtapiv1.Network{
+			Name:          name,
+			NetworkSource: kubevirtapiv1.NetworkSource{Multus: &kubevirtapiv1.MultusNetwork{NetworkName: name, Default: isDefault}},
+		}
+	}
+	iface := func(name string, binding string) kubevirtapiv1.Interface {
+		return kubevirtapiv1.Interface{
+			Name:                   name,
+			InterfaceBindingMethod: expandInterfaceBindingMethod(binding),
+		}
+	}
+
+	cases := []struct {
+		name                 string
+		interfaces           []kubevirtapiv1.Interface
+		networks             []kubevirtapiv1.Network
+		expectedErrorMessage string
+	}{
+		{
+			name:       "pod and multus networks",
+			interfaces: []kubevirtapiv1.Interface{iface("default", "InterfaceMasquerade"), iface("storage", "InterfaceBridge"), iface("sriov", "InterfaceSRIOV")},
+			networks:   []kubevirtapiv1.Network{podNetwork("default"), multusNetwork("storage", false), multusNetwork("sriov", false)},
+		},
+		{
+			name:                 "interface without network",
+			interfaces:           []kubevirtapiv1.Interface{iface("default", "InterfaceMasquerade"), iface("storage", "InterfaceBridge")},
+			networks:             []kubevirtapiv1.Network{podNetwork("default")},
+			expectedErrorMessage: "interface storage has no matching network",
+		},
+		{
+			name:                 "network without interface",
+			interfaces:           []kubevirtapiv1.Interface{iface("default", "InterfaceMasquerade")},
+			networks:             []kubevirtapiv1.Network{podNetwork("default"), multusNetwork("storage", false)},
+			expectedErrorMessage: "network storage has no matching interface",
+		},
+		{
+			name:                 "two default networks",
+			interfaces:           []kubevirtapiv1.Interface{iface("default", "InterfaceMasquerade"), iface("storage", "InterfaceBridge")},
+			networks:             []kubevirtapiv1.Network{podNetwork("default"), multusNetwork("storage", true)},
+			expectedErrorMessage: "only one network can be the default one, got default, storage",
+		},
+		{
+			name:                 "masquerade on multus network",
+			interfaces:           []kubevirtapiv1.Interface{iface("storage", "InterfaceMasquerade")},
+			networks:             []kubevirtapiv1.Network{multusNetwork("storage", true)},
+			expectedErrorMessage: "interface storage: binding method InterfaceMasquerade is not supported on multus network storage",
+		},
+		{
+			name:       "network with both sources",
+			interfaces: []kubevirtapiv1.Interface{iface("default", "InterfaceBridge")},
+			networks: []kubevirtapiv1.Network{{
+				Name: "default",
+				NetworkSource: kubevirtapiv1.NetworkSource{
+					Pod:    &kubevirtapiv1.PodNetwork{},
+					Multus: &kubevirtapiv1.MultusNetwork{NetworkName: "default"},
+				},
+			}},
+			expectedErrorMessage: "network default must have exactly one of pod or multus set",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := kubevirtapiv1.VirtualMachineInstanceSpec{Networks: tc.networks}
+			spec.Domain.Devices.Interfaces = tc.interfaces
+
+			err := ValidateNetworks(spec)
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
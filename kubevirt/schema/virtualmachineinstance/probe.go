@@ -4,22 +4,169 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
 )
 
-func probeFields() map[string]*schema.Schema {
+// probeHandlers are the probe actions, exactly one of which must be set.
+var probeHandlers = []string{"http_get", "tcp_socket", "exec", "guest_agent_ping"}
+
+// probeFields returns the fields of the probe found under key, which is needed to reference its
+// handlers as mutually exclusive.
+func probeFields(key string) map[string]*schema.Schema {
+	handlers := make([]string, len(probeHandlers))
+	for i, handler := range probeHandlers {
+		handlers[i] = key + ".0." + handler
+	}
+
 	return map[string]*schema.Schema{
-		// TODO nargaman
+		"http_get": {
+			Type:         schema.TypeList,
+			Description:  "HTTPGet specifies the http request to perform.",
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: handlers,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"path": {
+						Type:        schema.TypeString,
+						Description: "Path to access on the HTTP server.",
+						Optional:    true,
+					},
+					"port": probePortSchema(),
+					"host": {
+						Type:        schema.TypeString,
+						Description: "Host name to connect to, defaults to the vmi IP.",
+						Optional:    true,
+					},
+					"scheme": {
+						Type:        schema.TypeString,
+						Description: "Scheme to use for connecting to the host. Defaults to HTTP.",
+						Optional:    true,
+						ValidateFunc: validation.StringInSlice([]string{
+							string(k8sv1.URISchemeHTTP),
+							string(k8sv1.URISchemeHTTPS),
+						}, false),
+					},
+					"http_header": {
+						Type:        schema.TypeList,
+						Description: "Custom headers to set in the request. HTTP allows repeated headers.",
+						Optional:    true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:        schema.TypeString,
+									Description: "The header field name.",
+									Required:    true,
+								},
+								"value": {
+									Type:        schema.TypeString,
+									Description: "The header field value.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"tcp_socket": {
+			Type:         schema.TypeList,
+			Description:  "TCPSocket specifies an action involving a TCP port.",
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: handlers,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"port": probePortSchema(),
+					"host": {
+						Type:        schema.TypeString,
+						Description: "Host name to connect to, defaults to the vmi IP.",
+						Optional:    true,
+					},
+				},
+			},
+		},
+		"exec": {
+			Type:         schema.TypeList,
+			Description:  "Exec specifies a command to execute in the guest through the qemu guest agent, which must be installed in the guest. Exit status of 0 is treated as live/healthy and non-zero is unhealthy.",
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: handlers,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"command": {
+						Type:        schema.TypeList,
+						Description: "Command is the command line to execute inside the guest. The command is not run inside a shell, so traditional shell instructions ('|', etc) won't work. To use a shell, you need to explicitly call out to that shell.",
+						Required:    true,
+						MinItems:    1,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		"guest_agent_ping": {
+			Type:         schema.TypeList,
+			Description:  "GuestAgentPing contacts the qemu guest agent in the guest for availability.",
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: handlers,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{},
+			},
+		},
+		"initial_delay_seconds": {
+			Type:         schema.TypeInt,
+			Description:  "Number of seconds after the vmi has started before liveness probes are initiated.",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"timeout_seconds": {
+			Type:         schema.TypeInt,
+			Description:  "Number of seconds after which the probe times out. For exec probes the timeout fails the probe but does not terminate the command running on the guest. Defaults to 1 second. Minimum value is 1.",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"period_seconds": {
+			Type:         schema.TypeInt,
+			Description:  "How often (in seconds) to perform the probe. Defaults to 10 seconds. Minimum value is 1.",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"success_threshold": {
+			Type:         schema.TypeInt,
+			Description:  "Minimum consecutive successes for the probe to be considered successful after having failed. Defaults to 1. Must be 1 for liveness. Minimum value is 1.",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"failure_threshold": {
+			Type:         schema.TypeInt,
+			Description:  "Minimum consecutive failures for the probe to be considered failed after having succeeded. Defaults to 3. Minimum value is 1.",
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+	}
+}
+
+func probePortSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Number or name of the port to access on the vmi. A number must be in the range 1 to 65535, a name must be an IANA_SVC_NAME.",
+		Required:    true,
 	}
 }
 
-func probeSchema() *schema.Schema {
-	fields := probeFields()
+func probeSchema(key string) *schema.Schema {
+	fields := probeFields(key)
 
 	return &schema.Schema{
 		Type: schema.TypeList,
 
-		Description: fmt.Sprintf("Specification of the desired behavior of the VirtualMachineInstance on the host."),
+		Description: fmt.Sprintf("Specification of the desired behavior of the VirtualMachineInstance on the host. Exactly one of http_get, tcp_socket, exec or guest_agent_ping must be set."),
 		Optional:    true,
 		MaxItems:    1,
 		Elem: &schema.Resource{
@@ -35,10 +182,103 @@ func expandProbe(probe []interface{}) *kubevirtapiv1.Probe {
 	}
 
 	result := &kubevirtapiv1.Probe{}
+	in := probe[0].(map[string]interface{})
 
-	_ = probe[0].(map[string]interface{})
+	if v, ok := in["http_get"].([]interface{}); ok && len(v) > 0 {
+		result.HTTPGet = expandHTTPGetAction(v)
+	}
+	if v, ok := in["tcp_socket"].([]interface{}); ok && len(v) > 0 {
+		result.TCPSocket = expandTCPSocketAction(v)
+	}
+	if v, ok := in["exec"].([]interface{}); ok && len(v) > 0 {
+		result.Exec = expandExecAction(v)
+	}
+	if v, ok := in["guest_agent_ping"].([]interface{}); ok && len(v) > 0 {
+		result.GuestAgentPing = &kubevirtapiv1.GuestAgentPing{}
+	}
+	if v, ok := in["initial_delay_seconds"].(int); ok {
+		result.InitialDelaySeconds = int32(v)
+	}
+	if v, ok := in["timeout_seconds"].(int); ok {
+		result.TimeoutSeconds = int32(v)
+	}
+	if v, ok := in["period_seconds"].(int); ok {
+		result.PeriodSeconds = int32(v)
+	}
+	if v, ok := in["success_threshold"].(int); ok {
+		result.SuccessThreshold = int32(v)
+	}
+	if v, ok := in["failure_threshold"].(int); ok {
+		result.FailureThreshold = int32(v)
+	}
 
-	// TODO nargaman
+	return result
+}
+
+func expandHTTPGetAction(httpGet []interface{}) *k8sv1.HTTPGetAction {
+	result := &k8sv1.HTTPGetAction{}
+
+	if httpGet[0] == nil {
+		return result
+	}
+	in := httpGet[0].(map[string]interface{})
+
+	if v, ok := in["path"].(string); ok {
+		result.Path = v
+	}
+	if v, ok := in["port"].(string); ok {
+		result.Port = intstr.Parse(v)
+	}
+	if v, ok := in["host"].(string); ok {
+		result.Host = v
+	}
+	if v, ok := in["scheme"].(string); ok {
+		result.Scheme = k8sv1.URIScheme(v)
+	}
+	if v, ok := in["http_header"].([]interface{}); ok && len(v) > 0 {
+		result.HTTPHeaders = make([]k8sv1.HTTPHeader, len(v))
+		for i, header := range v {
+			h := header.(map[string]interface{})
+			result.HTTPHeaders[i].Name = h["name"].(string)
+			result.HTTPHeaders[i].Value = h["value"].(string)
+		}
+	}
+
+	return result
+}
+
+func expandTCPSocketAction(tcpSocket []interface{}) *k8sv1.TCPSocketAction {
+	result := &k8sv1.TCPSocketAction{}
+
+	if tcpSocket[0] == nil {
+		return result
+	}
+	in := tcpSocket[0].(map[string]interface{})
+
+	if v, ok := in["port"].(string); ok {
+		result.Port = intstr.Parse(v)
+	}
+	if v, ok := in["host"].(string); ok {
+		result.Host = v
+	}
+
+	return result
+}
+
+func expandExecAction(exec []interface{}) *k8sv1.ExecAction {
+	result := &k8sv1.ExecAction{}
+
+	if exec[0] == nil {
+		return result
+	}
+	in := exec[0].(map[string]interface{})
+
+	if v, ok := in["command"].([]interface{}); ok {
+		result.Command = make([]string, len(v))
+		for i, arg := range v {
+			result.Command[i] = arg.(string)
+		}
+	}
 
 	return result
 }
@@ -46,9 +286,63 @@ func expandProbe(probe []interface{}) *kubevirtapiv1.Probe {
 func flattenProbe(in kubevirtapiv1.Probe) []interface{} {
 	att := make(map[string]interface{})
 
-	// att["spec"] = flattenVirtualMachineInstanceSpecSpec(in.Spec)
-	// att["status"] = flattenVirtualMachineInstanceSpecStatus(in.Status)
-	// TODO nargaman
+	if in.HTTPGet != nil {
+		att["http_get"] = flattenHTTPGetAction(*in.HTTPGet)
+	}
+	if in.TCPSocket != nil {
+		att["tcp_socket"] = flattenTCPSocketAction(*in.TCPSocket)
+	}
+	if in.Exec != nil {
+		att["exec"] = flattenExecAction(*in.Exec)
+	}
+	if in.GuestAgentPing != nil {
+		att["guest_agent_ping"] = []interface{}{map[string]interface{}{}}
+	}
+	att["initial_delay_seconds"] = int(in.InitialDelaySeconds)
+	att["timeout_seconds"] = int(in.TimeoutSeconds)
+	att["period_seconds"] = int(in.PeriodSeconds)
+	att["success_threshold"] = int(in.SuccessThreshold)
+	att["failure_threshold"] = int(in.FailureThreshold)
+
+	return []interface{}{att}
+}
+
+func flattenHTTPGetAction(in k8sv1.HTTPGetAction) []interface{} {
+	att := make(map[string]interface{})
+
+	att["path"] = in.Path
+	att["port"] = in.Port.String()
+	att["host"] = in.Host
+	att["scheme"] = string(in.Scheme)
+	headers := make([]interface{}, len(in.HTTPHeaders))
+	for i, header := range in.HTTPHeaders {
+		headers[i] = map[string]interface{}{
+			"name":  header.Name,
+			"value": header.Value,
+		}
+	}
+	att["http_header"] = headers
+
+	return []interface{}{att}
+}
+
+func flattenTCPSocketAction(in k8sv1.TCPSocketAction) []interface{} {
+	att := make(map[string]interface{})
+
+	att["port"] = in.Port.String()
+	att["host"] = in.Host
+
+	return []interface{}{att}
+}
+
+func flattenExecAction(in k8sv1.ExecAction) []interface{} {
+	att := make(map[string]interface{})
+
+	command := make([]interface{}, len(in.Command))
+	for i, arg := range in.Command {
+		command[i] = arg
+	}
+	att["command"] = command
 
 	return []interface{}{att}
 }
@@ -0,0 +1,79 @@
+package virtualmachineinstance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"gotest.tools/assert"
+)
+
+func TestProbeHandlers(t *testing.T) {
+	// The probe handlers reference each other by their keys within the virtual machine resource.
+	resource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"spec": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"template": VirtualMachineInstanceTemplateSpecSchema(),
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		probe         map[string]interface{}
+		expectedError bool
+	}{
+		{
+			name: "guest agent ping",
+			probe: map[string]interface{}{
+				"guest_agent_ping": []interface{}{map[string]interface{}{}},
+			},
+		},
+		{
+			name: "no handler",
+			probe: map[string]interface{}{
+				"period_seconds": 10,
+			},
+			expectedError: true,
+		},
+		{
+			name: "two handlers",
+			probe: map[string]interface{}{
+				"tcp_socket":       []interface{}{map[string]interface{}{"port": "22"}},
+				"guest_agent_ping": []interface{}{map[string]interface{}{}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"spec": []interface{}{map[string]interface{}{
+					"template": []interface{}{map[string]interface{}{
+						"spec": []interface{}{map[string]interface{}{
+							"liveness_probe": []interface{}{tc.probe},
+						}},
+					}},
+				}},
+			})
+
+			probeErrors := 0
+			for _, d := range resource.Validate(config) {
+				if strings.Contains(d.Summary+d.Detail, "liveness_probe") {
+					probeErrors++
+				}
+			}
+			assert.Equal(t, probeErrors > 0, tc.expectedError)
+		})
+	}
+}
@@ -11,6 +11,10 @@ import (
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
 )
 
+// specKey is the key of the vmi spec within the virtual machine resource. Schema constraints such
+// as ConflictsWith reference other attributes by their absolute keys.
+const specKey = "spec.0.template.0.spec.0."
+
 func virtualMachineInstanceSpecFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"priority_class_name": {
@@ -31,13 +35,24 @@ func virtualMachineInstanceSpecFields() map[string]*schema.Schema {
 			Description: "If specified, the VMI will be dispatched by specified scheduler. If not specified, the VMI will be dispatched by default scheduler.",
 			Optional:    true,
 		},
-		"tolerations": k8s.TolerationSchema(),
+		"tolerations":                 k8s.TolerationSchema(),
+		"topology_spread_constraints": k8s.TopologySpreadConstraintSchema(),
 		"eviction_strategy": {
 			Type:        schema.TypeString,
-			Description: "EvictionStrategy can be set to \"LiveMigrate\" if the VirtualMachineInstance should be migrated instead of shut-off in case of a node drain.",
+			Description: "EvictionStrategy describes the strategy to follow when a node drain occurs. \"LiveMigrate\" migrates the VirtualMachineInstance instead of shutting it off, \"None\" shuts it off, and \"External\" leaves the eviction to an external controller. Defaults to the cluster-wide eviction strategy.",
+			Optional:    true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(kubevirtapiv1.EvictionStrategyNone),
+				string(kubevirtapiv1.EvictionStrategyLiveMigrate),
+				string(kubevirtapiv1.EvictionStrategyExternal),
+			}, false),
+		},
+		"start_strategy": {
+			Type:        schema.TypeString,
+			Description: "StartStrategy can be set to \"Paused\" if the VirtualMachineInstance should be started in a paused state.",
 			Optional:    true,
 			ValidateFunc: validation.StringInSlice([]string{
-				"LiveMigrate",
+				string(kubevirtapiv1.StartStrategyPaused),
 			}, false),
 		},
 		"termination_grace_period_seconds": {
@@ -46,8 +61,8 @@ func virtualMachineInstanceSpecFields() map[string]*schema.Schema {
 			Optional:    true,
 		},
 		"volume":          volumesSchema(),
-		"liveness_probe":  probeSchema(),
-		"readiness_probe": probeSchema(),
+		"liveness_probe":  probeSchema(specKey + "liveness_probe"),
+		"readiness_probe": probeSchema(specKey + "readiness_probe"),
 		"hostname": {
 			Type:        schema.TypeString,
 			Description: "Specifies the hostname of the vmi.",
@@ -70,7 +85,8 @@ func virtualMachineInstanceSpecFields() map[string]*schema.Schema {
 				"None",
 			}, false),
 		},
-		"pod_dns_config": k8s.PodDnsConfigSchema(),
+		"pod_dns_config":     k8s.PodDnsConfigSchema(),
+		"access_credentials": accessCredentialsSchema(),
 	}
 }
 
@@ -125,18 +141,29 @@ func expandVirtualMachineInstanceSpec(virtualMachineInstanceSpec []interface{})
 		}
 		result.Tolerations = tolerations
 	}
+	if v, ok := in["topology_spread_constraints"].([]interface{}); ok {
+		result.TopologySpreadConstraints = k8s.ExpandTopologySpreadConstraints(v)
+	}
 	if v, ok := in["eviction_strategy"].(string); ok {
 		if v != "" {
 			evictionStrategy := kubevirtapiv1.EvictionStrategy(v)
 			result.EvictionStrategy = &evictionStrategy
 		}
 	}
+	if v, ok := in["start_strategy"].(string); ok && v != "" {
+		startStrategy := kubevirtapiv1.StartStrategy(v)
+		result.StartStrategy = &startStrategy
+	}
 	if v, ok := in["termination_grace_period_seconds"].(int); ok {
 		seconds := int64(v)
 		result.TerminationGracePeriodSeconds = &seconds
 	}
 	if v, ok := in["volume"].([]interface{}); ok {
-		result.Volumes = expandVolumes(v)
+		volumes, err := expandVolumes(v)
+		if err != nil {
+			return result, err
+		}
+		result.Volumes = volumes
 	}
 	if v, ok := in["liveness_probe"].([]interface{}); ok {
 		result.LivenessProbe = expandProbe(v)
@@ -163,6 +190,13 @@ func expandVirtualMachineInstanceSpec(virtualMachineInstanceSpec []interface{})
 		}
 		result.DNSConfig = dnsConfig
 	}
+	if v, ok := in["access_credentials"].([]interface{}); ok && len(v) > 0 {
+		accessCredentials, err := expandAccessCredentials(v)
+		if err != nil {
+			return result, err
+		}
+		result.AccessCredentials = accessCredentials
+	}
 
 	return result, nil
 }
@@ -176,9 +210,13 @@ func flattenVirtualMachineInstanceSpec(in kubevirtapiv1.VirtualMachineInstanceSp
 	att["affinity"] = k8s.FlattenAffinity(in.Affinity)
 	att["scheduler_name"] = in.SchedulerName
 	att["tolerations"] = k8s.FlattenTolerations(in.Tolerations)
+	att["topology_spread_constraints"] = k8s.FlattenTopologySpreadConstraints(in.TopologySpreadConstraints)
 	if in.EvictionStrategy != nil {
 		att["eviction_strategy"] = string(*in.EvictionStrategy)
 	}
+	if in.StartStrategy != nil {
+		att["start_strategy"] = string(*in.StartStrategy)
+	}
 	if in.TerminationGracePeriodSeconds != nil {
 		att["termination_grace_period_seconds"] = *in.TerminationGracePeriodSeconds
 	}
@@ -196,6 +234,7 @@ func flattenVirtualMachineInstanceSpec(in kubevirtapiv1.VirtualMachineInstanceSp
 	if in.DNSConfig != nil {
 		att["pod_dns_config"] = k8s.FlattenPodDNSConfig(in.DNSConfig)
 	}
+	att["access_credentials"] = flattenAccessCredentials(in.AccessCredentials)
 
 	return []interface{}{att}
 }
@@ -4,10 +4,19 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/schema/k8s"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
 )
 
+var imagePullPolicies = []string{
+	string(k8sv1.PullAlways),
+	string(k8sv1.PullNever),
+	string(k8sv1.PullIfNotPresent),
+}
+
 func volumesFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"name": {
@@ -37,60 +46,168 @@ func volumesFields() map[string]*schema.Schema {
 							},
 						},
 					},
-					"cloud_init_config_drive": {
+					"cloud_init_config_drive": cloudInitSourceSchema("CloudInitConfigDrive represents a cloud-init Config Drive user-data source.", "config drive"),
+					"cloud_init_no_cloud":     cloudInitSourceSchema("CloudInitNoCloud represents a cloud-init NoCloud user-data source.", "NoCloud"),
+					"service_account": {
 						Type:        schema.TypeList,
-						Description: "CloudInitConfigDrive represents a cloud-init Config Drive user-data source.",
+						Description: "ServiceAccountVolumeSource represents a reference to a service account.",
 						MaxItems:    1,
 						Optional:    true,
 						Elem: &schema.Resource{
 							Schema: map[string]*schema.Schema{
-								"user_data_secret_ref": k8s.LocalObjectReferenceSchema("UserDataSecretRef references a k8s secret that contains config drive userdata."),
-								"user_data_base64": {
+								"service_account_name": {
 									Type:        schema.TypeString,
-									Description: "UserDataBase64 contains config drive cloud-init userdata as a base64 encoded string.",
-									Optional:    true,
+									Description: "Name of the service account in the pod's namespace to use.",
+									Required:    true,
 								},
-								"user_data": {
+							},
+						},
+					},
+					"container_disk": {
+						Type:        schema.TypeList,
+						Description: "ContainerDisk references a docker image, embedding a qcow or raw disk. More info: https://kubevirt.gitbooks.io/user-guide/registry-disk.html",
+						MaxItems:    1,
+						Optional:    true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"image": {
 									Type:        schema.TypeString,
-									Description: "UserData contains config drive inline cloud-init userdata.",
-									Optional:    true,
+									Description: "Image is the name of the image with the embedded disk.",
+									Required:    true,
 								},
-								"network_data_secret_ref": k8s.LocalObjectReferenceSchema("NetworkDataSecretRef references a k8s secret that contains config drive networkdata."),
-								"network_data_base64": {
+								"image_pull_policy": {
+									Type:         schema.TypeString,
+									Description:  "Image pull policy. One of Always, Never, IfNotPresent. Defaults to Always if :latest tag is specified, or IfNotPresent otherwise.",
+									Optional:     true,
+									ValidateFunc: validation.StringInSlice(imagePullPolicies, false),
+								},
+								"image_pull_secret": {
 									Type:        schema.TypeString,
-									Description: "NetworkDataBase64 contains config drive cloud-init networkdata as a base64 encoded string.",
+									Description: "ImagePullSecret is the name of the Docker registry secret required to pull the image. The secret must already exist.",
 									Optional:    true,
 								},
-								"network_data": {
+								"path": {
 									Type:        schema.TypeString,
-									Description: "NetworkData contains config drive inline cloud-init networkdata.",
+									Description: "Path defines the path to disk file in the container.",
 									Optional:    true,
 								},
 							},
 						},
 					},
-					"service_account": {
+					"config_map":       configMapVolumeSchema(),
+					"secret":           secretVolumeSchema(),
+					"downward_api":     downwardAPIVolumeSchema(),
+					"downward_metrics": downwardMetricsVolumeSchema(),
+					"persistent_volume_claim": {
 						Type:        schema.TypeList,
-						Description: "ServiceAccountVolumeSource represents a reference to a service account.",
+						Description: "PersistentVolumeClaim attaches an existing claim in the same namespace as the vmi.",
+						MaxItems:    1,
+						Optional:    true,
+						Elem: &schema.Resource{
+							Schema: persistentVolumeClaimFields(true),
+						},
+					},
+					"ephemeral": {
+						Type:        schema.TypeList,
+						Description: "Ephemeral is a special volume source that wraps a claim in a copy-on-write overlay. Writes are discarded when the vmi stops.",
 						MaxItems:    1,
 						Optional:    true,
 						Elem: &schema.Resource{
 							Schema: map[string]*schema.Schema{
-								"service_account_name": {
+								"persistent_volume_claim": {
+									Type:        schema.TypeList,
+									Description: "PersistentVolumeClaim is the claim used as the read-only backing image of the overlay.",
+									MaxItems:    1,
+									Required:    true,
+									Elem: &schema.Resource{
+										Schema: persistentVolumeClaimFields(false),
+									},
+								},
+							},
+						},
+					},
+					"empty_disk": {
+						Type:        schema.TypeList,
+						Description: "EmptyDisk represents a temporary disk which shares the vmi's lifecycle.",
+						MaxItems:    1,
+						Optional:    true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"capacity": {
+									Type:         schema.TypeString,
+									Description:  "Capacity of the sparse disk.",
+									Required:     true,
+									ValidateFunc: utils.ValidateResourceQuantity,
+								},
+							},
+						},
+					},
+					"host_disk": {
+						Type:        schema.TypeList,
+						Description: "HostDisk represents a disk created on the cluster level.",
+						MaxItems:    1,
+						Optional:    true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"path": {
 									Type:        schema.TypeString,
-									Description: "Name of the service account in the pod's namespace to use.",
+									Description: "The path to the HostDisk image located on the cluster.",
+									Required:    true,
+								},
+								"type": {
+									Type:        schema.TypeString,
+									Description: "Contains information if the disk.img file exists or should be created. Allowed options are 'Disk' and 'DiskOrCreate'.",
 									Required:    true,
+									ValidateFunc: validation.StringInSlice([]string{
+										string(kubevirtapiv1.HostDiskExists),
+										string(kubevirtapiv1.HostDiskExistsOrCreate),
+									}, false),
+								},
+								"capacity": {
+									Type:         schema.TypeString,
+									Description:  "Capacity of the sparse disk. Required with the DiskOrCreate type.",
+									Optional:     true,
+									ValidateFunc: utils.ValidateResourceQuantity,
+								},
+								"shared": {
+									Type:        schema.TypeBool,
+									Description: "Shared indicates whether the path is shared between nodes.",
+									Optional:    true,
 								},
 							},
 						},
 					},
-					// TODO nargaman - Add other data volume source types
 				},
 			},
 		},
 	}
 }
 
+// persistentVolumeClaimFields describes a reference to a claim in the vmi's namespace, which
+// may be hotplugged unless it backs an ephemeral volume.
+func persistentVolumeClaimFields(hotpluggable bool) map[string]*schema.Schema {
+	fields := map[string]*schema.Schema{
+		"claim_name": {
+			Type:        schema.TypeString,
+			Description: "ClaimName is the name of a PersistentVolumeClaim in the same namespace as the vmi.",
+			Required:    true,
+		},
+		"read_only": {
+			Type:        schema.TypeBool,
+			Description: "Will force the ReadOnly setting in VolumeMounts.",
+			Optional:    true,
+		},
+	}
+	if hotpluggable {
+		fields["hotpluggable"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Description: "Hotpluggable indicates whether the volume can be hotplugged and hotunplugged. Requires the HotplugVolumes feature gate.",
+			Optional:    true,
+		}
+	}
+	return fields
+}
+
 func volumesSchema() *schema.Schema {
 	fields := volumesFields()
 
@@ -106,11 +223,11 @@ func volumesSchema() *schema.Schema {
 
 }
 
-func expandVolumes(volumes []interface{}) []kubevirtapiv1.Volume {
+func expandVolumes(volumes []interface{}) ([]kubevirtapiv1.Volume, error) {
 	result := make([]kubevirtapiv1.Volume, len(volumes))
 
 	if len(volumes) == 0 || volumes[0] == nil {
-		return result
+		return result, nil
 	}
 
 	for i, condition := range volumes {
@@ -120,18 +237,22 @@ func expandVolumes(volumes []interface{}) []kubevirtapiv1.Volume {
 			result[i].Name = v
 		}
 		if v, ok := in["volume_source"].([]interface{}); ok {
-			result[i].VolumeSource = expandVolumeSource(v)
+			volumeSource, err := expandVolumeSource(v)
+			if err != nil {
+				return result, fmt.Errorf("volume %s: %s", result[i].Name, err)
+			}
+			result[i].VolumeSource = volumeSource
 		}
 	}
 
-	return result
+	return result, nil
 }
 
-func expandVolumeSource(volumeSource []interface{}) kubevirtapiv1.VolumeSource {
+func expandVolumeSource(volumeSource []interface{}) (kubevirtapiv1.VolumeSource, error) {
 	result := kubevirtapiv1.VolumeSource{}
 
 	if len(volumeSource) == 0 || volumeSource[0] == nil {
-		return result
+		return result, nil
 	}
 
 	in := volumeSource[0].(map[string]interface{})
@@ -140,13 +261,57 @@ func expandVolumeSource(volumeSource []interface{}) kubevirtapiv1.VolumeSource {
 		result.DataVolume = expandDataVolume(v)
 	}
 	if v, ok := in["cloud_init_config_drive"].([]interface{}); ok {
-		result.CloudInitConfigDrive = expandCloudInitConfigDrive(v)
+		result.CloudInitConfigDrive = expandCloudInitSource(v)
+	}
+	if v, ok := in["cloud_init_no_cloud"].([]interface{}); ok {
+		if source := expandCloudInitSource(v); source != nil {
+			result.CloudInitNoCloud = (*kubevirtapiv1.CloudInitNoCloudSource)(source)
+		}
 	}
 	if v, ok := in["service_account"].([]interface{}); ok {
 		result.ServiceAccount = expandServiceAccount(v)
 	}
+	if v, ok := in["container_disk"].([]interface{}); ok {
+		result.ContainerDisk = expandContainerDisk(v)
+	}
+	if v, ok := in["config_map"].([]interface{}); ok {
+		result.ConfigMap = expandConfigMapVolume(v)
+	}
+	if v, ok := in["secret"].([]interface{}); ok {
+		result.Secret = expandSecretVolume(v)
+	}
+	if v, ok := in["downward_api"].([]interface{}); ok {
+		downwardAPI, err := expandDownwardAPIVolume(v)
+		if err != nil {
+			return result, err
+		}
+		result.DownwardAPI = downwardAPI
+	}
+	if v, ok := in["downward_metrics"].([]interface{}); ok {
+		result.DownwardMetrics = expandDownwardMetricsVolume(v)
+	}
+	if v, ok := in["persistent_volume_claim"].([]interface{}); ok {
+		result.PersistentVolumeClaim = expandPersistentVolumeClaim(v)
+	}
+	if v, ok := in["ephemeral"].([]interface{}); ok {
+		result.Ephemeral = expandEphemeral(v)
+	}
+	if v, ok := in["empty_disk"].([]interface{}); ok {
+		emptyDisk, err := expandEmptyDisk(v)
+		if err != nil {
+			return result, err
+		}
+		result.EmptyDisk = emptyDisk
+	}
+	if v, ok := in["host_disk"].([]interface{}); ok {
+		hostDisk, err := expandHostDisk(v)
+		if err != nil {
+			return result, err
+		}
+		result.HostDisk = hostDisk
+	}
 
-	return result
+	return result, nil
 }
 
 func expandDataVolume(dataVolumeSource []interface{}) *kubevirtapiv1.DataVolumeSource {
@@ -164,51 +329,146 @@ func expandDataVolume(dataVolumeSource []interface{}) *kubevirtapiv1.DataVolumeS
 	return result
 }
 
-func expandCloudInitConfigDrive(cloudInitConfigDriveSource []interface{}) *kubevirtapiv1.CloudInitConfigDriveSource {
-	if len(cloudInitConfigDriveSource) == 0 || cloudInitConfigDriveSource[0] == nil {
+func expandServiceAccount(serviceAccountSource []interface{}) *kubevirtapiv1.ServiceAccountVolumeSource {
+	if len(serviceAccountSource) == 0 || serviceAccountSource[0] == nil {
 		return nil
 	}
 
-	result := &kubevirtapiv1.CloudInitConfigDriveSource{}
-	in := cloudInitConfigDriveSource[0].(map[string]interface{})
+	result := &kubevirtapiv1.ServiceAccountVolumeSource{}
+	in := serviceAccountSource[0].(map[string]interface{})
 
-	if v, ok := in["user_data_secret_ref"].([]interface{}); ok {
-		result.UserDataSecretRef = k8s.ExpandLocalObjectReferences(v)
+	if v, ok := in["service_account_name"].(string); ok {
+		result.ServiceAccountName = v
 	}
-	if v, ok := in["user_data_base64"].(string); ok {
-		result.UserDataBase64 = v
+
+	return result
+}
+
+func expandContainerDisk(containerDiskSource []interface{}) *kubevirtapiv1.ContainerDiskSource {
+	if len(containerDiskSource) == 0 || containerDiskSource[0] == nil {
+		return nil
 	}
-	if v, ok := in["user_data"].(string); ok {
-		result.UserData = v
+
+	result := &kubevirtapiv1.ContainerDiskSource{}
+	in := containerDiskSource[0].(map[string]interface{})
+
+	if v, ok := in["image"].(string); ok {
+		result.Image = v
 	}
-	if v, ok := in["network_data_secret_ref"].([]interface{}); ok {
-		result.NetworkDataSecretRef = k8s.ExpandLocalObjectReferences(v)
+	if v, ok := in["image_pull_policy"].(string); ok {
+		result.ImagePullPolicy = k8sv1.PullPolicy(v)
 	}
-	if v, ok := in["network_data_base64"].(string); ok {
-		result.NetworkDataBase64 = v
+	if v, ok := in["image_pull_secret"].(string); ok {
+		result.ImagePullSecret = v
 	}
-	if v, ok := in["network_data"].(string); ok {
-		result.NetworkData = v
+	if v, ok := in["path"].(string); ok {
+		result.Path = v
 	}
 
 	return result
 }
 
-func expandServiceAccount(serviceAccountSource []interface{}) *kubevirtapiv1.ServiceAccountVolumeSource {
-	if len(serviceAccountSource) == 0 || serviceAccountSource[0] == nil {
+func expandPersistentVolumeClaim(persistentVolumeClaimSource []interface{}) *kubevirtapiv1.PersistentVolumeClaimVolumeSource {
+	if len(persistentVolumeClaimSource) == 0 || persistentVolumeClaimSource[0] == nil {
 		return nil
 	}
 
-	result := &kubevirtapiv1.ServiceAccountVolumeSource{}
-	in := serviceAccountSource[0].(map[string]interface{})
+	result := &kubevirtapiv1.PersistentVolumeClaimVolumeSource{}
+	in := persistentVolumeClaimSource[0].(map[string]interface{})
 
-	if v, ok := in["service_account_name"].(string); ok {
-		result.ServiceAccountName = v
+	if claim := expandClaimVolumeSource(persistentVolumeClaimSource); claim != nil {
+		result.PersistentVolumeClaimVolumeSource = *claim
+	}
+	if v, ok := in["hotpluggable"].(bool); ok {
+		result.Hotpluggable = v
+	}
+
+	return result
+}
+
+func expandClaimVolumeSource(claimSource []interface{}) *k8sv1.PersistentVolumeClaimVolumeSource {
+	if len(claimSource) == 0 || claimSource[0] == nil {
+		return nil
+	}
+
+	result := &k8sv1.PersistentVolumeClaimVolumeSource{}
+	in := claimSource[0].(map[string]interface{})
+
+	if v, ok := in["claim_name"].(string); ok {
+		result.ClaimName = v
+	}
+	if v, ok := in["read_only"].(bool); ok {
+		result.ReadOnly = v
+	}
+
+	return result
+}
+
+func expandEphemeral(ephemeralSource []interface{}) *kubevirtapiv1.EphemeralVolumeSource {
+	if len(ephemeralSource) == 0 || ephemeralSource[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.EphemeralVolumeSource{}
+	in := ephemeralSource[0].(map[string]interface{})
+
+	if v, ok := in["persistent_volume_claim"].([]interface{}); ok {
+		result.PersistentVolumeClaim = expandClaimVolumeSource(v)
 	}
 
 	return result
 }
 
+func expandEmptyDisk(emptyDiskSource []interface{}) (*kubevirtapiv1.EmptyDiskSource, error) {
+	if len(emptyDiskSource) == 0 || emptyDiskSource[0] == nil {
+		return nil, nil
+	}
+
+	result := &kubevirtapiv1.EmptyDiskSource{}
+	in := emptyDiskSource[0].(map[string]interface{})
+
+	if v, ok := in["capacity"].(string); ok && v != "" {
+		capacity, err := resource.ParseQuantity(v)
+		if err != nil {
+			return result, err
+		}
+		result.Capacity = capacity
+	}
+
+	return result, nil
+}
+
+func expandHostDisk(hostDiskSource []interface{}) (*kubevirtapiv1.HostDisk, error) {
+	if len(hostDiskSource) == 0 || hostDiskSource[0] == nil {
+		return nil, nil
+	}
+
+	result := &kubevirtapiv1.HostDisk{}
+	in := hostDiskSource[0].(map[string]interface{})
+
+	if v, ok := in["path"].(string); ok {
+		result.Path = v
+	}
+	if v, ok := in["type"].(string); ok {
+		result.Type = kubevirtapiv1.HostDiskType(v)
+	}
+	if v, ok := in["capacity"].(string); ok && v != "" {
+		capacity, err := resource.ParseQuantity(v)
+		if err != nil {
+			return result, err
+		}
+		result.Capacity = capacity
+	}
+	if v, ok := in["shared"].(bool); ok && v {
+		result.Shared = &v
+	}
+	if result.Type == kubevirtapiv1.HostDiskExistsOrCreate && result.Capacity.IsZero() {
+		return result, fmt.Errorf("host_disk capacity is required with the %s type", kubevirtapiv1.HostDiskExistsOrCreate)
+	}
+
+	return result, nil
+}
+
 func flattenVolumes(in []kubevirtapiv1.Volume) []interface{} {
 	att := make([]interface{}, len(in))
 
@@ -231,11 +491,41 @@ func flattenVolumeSource(in kubevirtapiv1.VolumeSource) []interface{} {
 		att["data_volume"] = flattenDataVolume(*in.DataVolume)
 	}
 	if in.CloudInitConfigDrive != nil {
-		att["cloud_init_config_drive"] = flattenCloudInitConfigDrive(*in.CloudInitConfigDrive)
+		att["cloud_init_config_drive"] = flattenCloudInitSource(*in.CloudInitConfigDrive)
+	}
+	if in.CloudInitNoCloud != nil {
+		att["cloud_init_no_cloud"] = flattenCloudInitSource(kubevirtapiv1.CloudInitConfigDriveSource(*in.CloudInitNoCloud))
 	}
 	if in.ServiceAccount != nil {
 		att["service_account"] = flattenServiceAccount(*in.ServiceAccount)
 	}
+	if in.ContainerDisk != nil {
+		att["container_disk"] = flattenContainerDisk(*in.ContainerDisk)
+	}
+	if in.ConfigMap != nil {
+		att["config_map"] = flattenConfigMapVolume(*in.ConfigMap)
+	}
+	if in.Secret != nil {
+		att["secret"] = flattenSecretVolume(*in.Secret)
+	}
+	if in.DownwardAPI != nil {
+		att["downward_api"] = flattenDownwardAPIVolume(*in.DownwardAPI)
+	}
+	if in.DownwardMetrics != nil {
+		att["downward_metrics"] = flattenDownwardMetricsVolume(*in.DownwardMetrics)
+	}
+	if in.PersistentVolumeClaim != nil {
+		att["persistent_volume_claim"] = flattenPersistentVolumeClaim(*in.PersistentVolumeClaim)
+	}
+	if in.Ephemeral != nil {
+		att["ephemeral"] = flattenEphemeral(*in.Ephemeral)
+	}
+	if in.EmptyDisk != nil {
+		att["empty_disk"] = flattenEmptyDisk(*in.EmptyDisk)
+	}
+	if in.HostDisk != nil {
+		att["host_disk"] = flattenHostDisk(*in.HostDisk)
+	}
 
 	return []interface{}{att}
 }
@@ -248,27 +538,69 @@ func flattenDataVolume(in kubevirtapiv1.DataVolumeSource) []interface{} {
 	return []interface{}{att}
 }
 
-func flattenCloudInitConfigDrive(in kubevirtapiv1.CloudInitConfigDriveSource) []interface{} {
+func flattenServiceAccount(in kubevirtapiv1.ServiceAccountVolumeSource) []interface{} {
 	att := make(map[string]interface{})
 
-	if in.UserDataSecretRef != nil {
-		att["user_data_secret_ref"] = k8s.FlattenLocalObjectReferences(*in.UserDataSecretRef)
-	}
-	att["user_data_base64"] = in.UserDataBase64
-	att["user_data"] = in.UserData
-	if in.NetworkDataSecretRef != nil {
-		att["network_data_secret_ref"] = k8s.FlattenLocalObjectReferences(*in.NetworkDataSecretRef)
+	att["service_account_name"] = in.ServiceAccountName
+
+	return []interface{}{att}
+}
+
+func flattenContainerDisk(in kubevirtapiv1.ContainerDiskSource) []interface{} {
+	att := make(map[string]interface{})
+
+	att["image"] = in.Image
+	att["image_pull_policy"] = string(in.ImagePullPolicy)
+	att["image_pull_secret"] = in.ImagePullSecret
+	att["path"] = in.Path
+
+	return []interface{}{att}
+}
+
+func flattenPersistentVolumeClaim(in kubevirtapiv1.PersistentVolumeClaimVolumeSource) []interface{} {
+	att := flattenClaimVolumeSource(in.PersistentVolumeClaimVolumeSource)
+
+	att[0].(map[string]interface{})["hotpluggable"] = in.Hotpluggable
+
+	return att
+}
+
+func flattenClaimVolumeSource(in k8sv1.PersistentVolumeClaimVolumeSource) []interface{} {
+	att := make(map[string]interface{})
+
+	att["claim_name"] = in.ClaimName
+	att["read_only"] = in.ReadOnly
+
+	return []interface{}{att}
+}
+
+func flattenEphemeral(in kubevirtapiv1.EphemeralVolumeSource) []interface{} {
+	att := make(map[string]interface{})
+
+	if in.PersistentVolumeClaim != nil {
+		att["persistent_volume_claim"] = flattenClaimVolumeSource(*in.PersistentVolumeClaim)
 	}
-	att["network_data_base64"] = in.NetworkDataBase64
-	att["network_data"] = in.NetworkData
 
 	return []interface{}{att}
 }
 
-func flattenServiceAccount(in kubevirtapiv1.ServiceAccountVolumeSource) []interface{} {
+func flattenEmptyDisk(in kubevirtapiv1.EmptyDiskSource) []interface{} {
 	att := make(map[string]interface{})
 
-	att["service_account_name"] = in.ServiceAccountName
+	att["capacity"] = in.Capacity.String()
+
+	return []interface{}{att}
+}
+
+func flattenHostDisk(in kubevirtapiv1.HostDisk) []interface{} {
+	att := make(map[string]interface{})
+
+	att["path"] = in.Path
+	att["type"] = string(in.Type)
+	if !in.Capacity.IsZero() {
+		att["capacity"] = in.Capacity.String()
+	}
+	att["shared"] = in.Shared != nil && *in.Shared
 
 	return []interface{}{att}
 }
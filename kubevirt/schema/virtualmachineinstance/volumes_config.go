@@ -0,0 +1,361 @@
+package virtualmachineinstance
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/kubevirt/terraform-provider-kubevirt/kubevirt/utils"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+)
+
+// The config volume sources expose data of Kubernetes objects to the guest, either as a disk
+// or as a virtiofs filesystem.
+
+func volumeLabelSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The volume label of the resulting disk inside the VMI. Different bootstrapping mechanisms require different values. Typical values are \"cidata\" (cloud-init), \"config-2\" (cloud-init) or \"OEMDRV\" (kickstart).",
+		Optional:    true,
+	}
+}
+
+func configMapVolumeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "ConfigMap represents a ConfigMap to share with the vmi.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Description: "Name of the ConfigMap in the vmi's namespace.",
+					Required:    true,
+				},
+				"optional": {
+					Type:        schema.TypeBool,
+					Description: "Specify whether the ConfigMap or its keys must be defined.",
+					Optional:    true,
+				},
+				"volume_label": volumeLabelSchema(),
+			},
+		},
+	}
+}
+
+func secretVolumeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Secret represents a Secret to share with the vmi.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"secret_name": {
+					Type:        schema.TypeString,
+					Description: "Name of the Secret in the vmi's namespace.",
+					Required:    true,
+				},
+				"optional": {
+					Type:        schema.TypeBool,
+					Description: "Specify whether the Secret or its keys must be defined.",
+					Optional:    true,
+				},
+				"volume_label": volumeLabelSchema(),
+			},
+		},
+	}
+}
+
+func downwardAPIVolumeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "DownwardAPI represents downward API about the pod that should populate this volume.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"field": {
+					Type:        schema.TypeList,
+					Description: "Fields is a list of downward API volume file.",
+					Optional:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"path": {
+								Type:        schema.TypeString,
+								Description: "Relative path name of the file to be created. Must not be absolute or contain the '..' path.",
+								Required:    true,
+							},
+							"field_ref": {
+								Type:        schema.TypeList,
+								Description: "Selects a field of the pod: only annotations, labels, name and namespace are supported.",
+								MaxItems:    1,
+								Optional:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"api_version": {
+											Type:        schema.TypeString,
+											Description: "Version of the schema the field_path is written in terms of. Defaults to \"v1\".",
+											Optional:    true,
+										},
+										"field_path": {
+											Type:        schema.TypeString,
+											Description: "Path of the field to select in the specified API version.",
+											Required:    true,
+										},
+									},
+								},
+							},
+							"resource_field_ref": {
+								Type:        schema.TypeList,
+								Description: "Selects a resource of the container: only resources limits and requests (limits.cpu, limits.memory, requests.cpu and requests.memory) are currently supported.",
+								MaxItems:    1,
+								Optional:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"container_name": {
+											Type:        schema.TypeString,
+											Description: "Container name: required for volumes, optional for env vars.",
+											Optional:    true,
+										},
+										"resource": {
+											Type:        schema.TypeString,
+											Description: "Required: resource to select.",
+											Required:    true,
+										},
+										"divisor": {
+											Type:         schema.TypeString,
+											Description:  "Specifies the output format of the exposed resources, defaults to \"1\".",
+											Optional:     true,
+											ValidateFunc: utils.ValidateResourceQuantity,
+										},
+									},
+								},
+							},
+							"mode": {
+								Type:         schema.TypeInt,
+								Description:  "Mode bits used to set permissions on this file, must be an octal value between 0000 and 0777 or a decimal value between 0 and 511.",
+								Optional:     true,
+								ValidateFunc: validation.IntBetween(0, 0777),
+							},
+						},
+					},
+				},
+				"volume_label": volumeLabelSchema(),
+			},
+		},
+	}
+}
+
+func downwardMetricsVolumeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "DownwardMetrics adds a very small disk to the vmi which contains a limited view of host and guest metrics. The disk content is compatible with vhostmd and vm-dump-metrics. Requires the DownwardMetrics feature gate.",
+		MaxItems:    1,
+		Optional:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{},
+		},
+	}
+}
+
+func expandConfigMapVolume(configMapSource []interface{}) *kubevirtapiv1.ConfigMapVolumeSource {
+	if len(configMapSource) == 0 || configMapSource[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.ConfigMapVolumeSource{}
+	in := configMapSource[0].(map[string]interface{})
+
+	if v, ok := in["name"].(string); ok {
+		result.Name = v
+	}
+	if v, ok := in["optional"].(bool); ok && v {
+		result.Optional = &v
+	}
+	if v, ok := in["volume_label"].(string); ok {
+		result.VolumeLabel = v
+	}
+
+	return result
+}
+
+func expandSecretVolume(secretSource []interface{}) *kubevirtapiv1.SecretVolumeSource {
+	if len(secretSource) == 0 || secretSource[0] == nil {
+		return nil
+	}
+
+	result := &kubevirtapiv1.SecretVolumeSource{}
+	in := secretSource[0].(map[string]interface{})
+
+	if v, ok := in["secret_name"].(string); ok {
+		result.SecretName = v
+	}
+	if v, ok := in["optional"].(bool); ok && v {
+		result.Optional = &v
+	}
+	if v, ok := in["volume_label"].(string); ok {
+		result.VolumeLabel = v
+	}
+
+	return result
+}
+
+func expandDownwardAPIVolume(downwardAPISource []interface{}) (*kubevirtapiv1.DownwardAPIVolumeSource, error) {
+	if len(downwardAPISource) == 0 {
+		return nil, nil
+	}
+
+	result := &kubevirtapiv1.DownwardAPIVolumeSource{}
+	if downwardAPISource[0] == nil {
+		return result, nil
+	}
+	in := downwardAPISource[0].(map[string]interface{})
+
+	if v, ok := in["field"].([]interface{}); ok && len(v) > 0 {
+		result.Fields = make([]k8sv1.DownwardAPIVolumeFile, len(v))
+		for i, field := range v {
+			f := field.(map[string]interface{})
+
+			result.Fields[i].Path = f["path"].(string)
+			if ref, ok := f["field_ref"].([]interface{}); ok && len(ref) > 0 && ref[0] != nil {
+				r := ref[0].(map[string]interface{})
+				result.Fields[i].FieldRef = &k8sv1.ObjectFieldSelector{
+					APIVersion: r["api_version"].(string),
+					FieldPath:  r["field_path"].(string),
+				}
+			}
+			if ref, ok := f["resource_field_ref"].([]interface{}); ok && len(ref) > 0 && ref[0] != nil {
+				r := ref[0].(map[string]interface{})
+				result.Fields[i].ResourceFieldRef = &k8sv1.ResourceFieldSelector{
+					ContainerName: r["container_name"].(string),
+					Resource:      r["resource"].(string),
+				}
+				if divisor, ok := r["divisor"].(string); ok && divisor != "" {
+					quantity, err := resource.ParseQuantity(divisor)
+					if err != nil {
+						return result, err
+					}
+					result.Fields[i].ResourceFieldRef.Divisor = quantity
+				}
+			}
+			if mode, ok := f["mode"].(int); ok && mode > 0 {
+				m := int32(mode)
+				result.Fields[i].Mode = &m
+			}
+		}
+	}
+	if v, ok := in["volume_label"].(string); ok {
+		result.VolumeLabel = v
+	}
+
+	return result, nil
+}
+
+func expandDownwardMetricsVolume(downwardMetricsSource []interface{}) *kubevirtapiv1.DownwardMetricsVolumeSource {
+	if len(downwardMetricsSource) == 0 {
+		return nil
+	}
+
+	return &kubevirtapiv1.DownwardMetricsVolumeSource{}
+}
+
+func flattenConfigMapVolume(in kubevirtapiv1.ConfigMapVolumeSource) []interface{} {
+	att := make(map[string]interface{})
+
+	att["name"] = in.Name
+	att["optional"] = in.Optional != nil && *in.Optional
+	att["volume_label"] = in.VolumeLabel
+
+	return []interface{}{att}
+}
+
+func flattenSecretVolume(in kubevirtapiv1.SecretVolumeSource) []interface{} {
+	att := make(map[string]interface{})
+
+	att["secret_name"] = in.SecretName
+	att["optional"] = in.Optional != nil && *in.Optional
+	att["volume_label"] = in.VolumeLabel
+
+	return []interface{}{att}
+}
+
+func flattenDownwardAPIVolume(in kubevirtapiv1.DownwardAPIVolumeSource) []interface{} {
+	att := make(map[string]interface{})
+
+	fields := make([]interface{}, len(in.Fields))
+	for i, v := range in.Fields {
+		c := make(map[string]interface{})
+
+		c["path"] = v.Path
+		if v.FieldRef != nil {
+			c["field_ref"] = []interface{}{map[string]interface{}{
+				"api_version": v.FieldRef.APIVersion,
+				"field_path":  v.FieldRef.FieldPath,
+			}}
+		}
+		if v.ResourceFieldRef != nil {
+			ref := map[string]interface{}{
+				"container_name": v.ResourceFieldRef.ContainerName,
+				"resource":       v.ResourceFieldRef.Resource,
+			}
+			if !v.ResourceFieldRef.Divisor.IsZero() {
+				ref["divisor"] = v.ResourceFieldRef.Divisor.String()
+			}
+			c["resource_field_ref"] = []interface{}{ref}
+		}
+		if v.Mode != nil {
+			c["mode"] = int(*v.Mode)
+		}
+
+		fields[i] = c
+	}
+	att["field"] = fields
+	att["volume_label"] = in.VolumeLabel
+
+	return []interface{}{att}
+}
+
+func flattenDownwardMetricsVolume(in kubevirtapiv1.DownwardMetricsVolumeSource) []interface{} {
+	return []interface{}{map[string]interface{}{}}
+}
+
+// ValidateVolumes checks that every config map, secret, downward API and downward metrics volume
+// is exposed to the guest by a disk of the same name. All but downward metrics may be exposed by a
+// filesystem instead.
+func ValidateVolumes(spec kubevirtapiv1.VirtualMachineInstanceSpec) error {
+	disks := make(map[string]bool)
+	for _, disk := range spec.Domain.Devices.Disks {
+		disks[disk.Name] = true
+	}
+	filesystems := make(map[string]bool)
+	for _, filesystem := range spec.Domain.Devices.Filesystems {
+		filesystems[filesystem.Name] = true
+	}
+
+	for _, volume := range spec.Volumes {
+		var kind string
+		switch {
+		case volume.ConfigMap != nil:
+			kind = "config_map"
+		case volume.Secret != nil:
+			kind = "secret"
+		case volume.DownwardAPI != nil:
+			kind = "downward_api"
+		case volume.DownwardMetrics != nil:
+			if !disks[volume.Name] {
+				return fmt.Errorf("downward_metrics volume %s has no matching disk", volume.Name)
+			}
+			continue
+		default:
+			continue
+		}
+		if !disks[volume.Name] && !filesystems[volume.Name] {
+			return fmt.Errorf("%s volume %s has no matching disk or filesystem", kind, volume.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package virtualmachineinstance
+
+import (
+	"testing"
+
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+
+	"gotest.tools/assert"
+)
+
+func TestValidateVolumes(t *testing.T) {
+	volume := func(name string, source kubevirtapiv1.VolumeSource) kubevirtapiv1.Volume {
+		return kubevirtapiv1.Volume{Name: name, VolumeSource: source}
+	}
+
+	cases := []struct {
+		name                 string
+		disks                []string
+		filesystems          []string
+		volumes              []kubevirtapiv1.Volume
+		expectedErrorMessage string
+	}{
+		{
+			name:        "config volumes with disks and filesystems",
+			disks:       []string{"configmap", "metrics"},
+			filesystems: []string{"secret"},
+			volumes: []kubevirtapiv1.Volume{
+				volume("configmap", kubevirtapiv1.VolumeSource{ConfigMap: &kubevirtapiv1.ConfigMapVolumeSource{}}),
+				volume("secret", kubevirtapiv1.VolumeSource{Secret: &kubevirtapiv1.SecretVolumeSource{}}),
+				volume("metrics", kubevirtapiv1.VolumeSource{DownwardMetrics: &kubevirtapiv1.DownwardMetricsVolumeSource{}}),
+			},
+		},
+		{
+			name:                 "downward api without device",
+			volumes:              []kubevirtapiv1.Volume{volume("downwardapi", kubevirtapiv1.VolumeSource{DownwardAPI: &kubevirtapiv1.DownwardAPIVolumeSource{}})},
+			expectedErrorMessage: "downward_api volume downwardapi has no matching disk or filesystem",
+		},
+		{
+			name:                 "downward metrics on a filesystem",
+			filesystems:          []string{"metrics"},
+			volumes:              []kubevirtapiv1.Volume{volume("metrics", kubevirtapiv1.VolumeSource{DownwardMetrics: &kubevirtapiv1.DownwardMetricsVolumeSource{}})},
+			expectedErrorMessage: "downward_metrics volume metrics has no matching disk",
+		},
+		{
+			name:    "other volumes are not checked",
+			volumes: []kubevirtapiv1.Volume{volume("rootdisk", kubevirtapiv1.VolumeSource{DataVolume: &kubevirtapiv1.DataVolumeSource{Name: "rootdisk"}})},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := kubevirtapiv1.VirtualMachineInstanceSpec{Volumes: tc.volumes}
+			for _, name := range tc.disks {
+				spec.Domain.Devices.Disks = append(spec.Domain.Devices.Disks, kubevirtapiv1.Disk{Name: name})
+			}
+			for _, name := range tc.filesystems {
+				spec.Domain.Devices.Filesystems = append(spec.Domain.Devices.Filesystems, kubevirtapiv1.Filesystem{Name: name})
+			}
+
+			err := ValidateVolumes(spec)
+			if tc.expectedErrorMessage == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 
 	kubevirtapiv1 "kubevirt.io/api/core/v1"
@@ -25,6 +26,12 @@ func getDataVolumeSpec() cdiv1.DataVolumeSpec {
 				Namespace: "namespace",
 				Name:      "name",
 			},
+			Registry: &cdiv1.DataVolumeSourceRegistry{
+				URL:           (func() *string { str := "docker://registry.example.com/golden/fedora:38"; return &str })(),
+				PullMethod:    (func() *cdiv1.RegistryPullMethod { method := cdiv1.RegistryPullNode; return &method })(),
+				SecretRef:     (func() *string { str := "registry-credentials"; return &str })(),
+				CertConfigMap: (func() *string { str := "registry-ca"; return &str })(),
+			},
 		},
 		PVC: &k8sv1.PersistentVolumeClaimSpec{
 			AccessModes: []k8sv1.PersistentVolumeAccessMode{
@@ -41,6 +48,18 @@ func getDataVolumeSpec() cdiv1.DataVolumeSpec {
 			Selector:         test_entities.LabelSelectorAPI,
 			VolumeName:       "volume_name",
 			StorageClassName: (func() *string { str := "standard"; return &str })(),
+			VolumeMode:       (func() *k8sv1.PersistentVolumeMode { mode := k8sv1.PersistentVolumeBlock; return &mode })(),
+			DataSource: &k8sv1.TypedLocalObjectReference{
+				APIGroup: (func() *string { str := "snapshot.storage.k8s.io"; return &str })(),
+				Kind:     "VolumeSnapshot",
+				Name:     "golden-snapshot",
+			},
+			DataSourceRef: &k8sv1.TypedObjectReference{
+				APIGroup:  (func() *string { str := "snapshot.storage.k8s.io"; return &str })(),
+				Kind:      "VolumeSnapshot",
+				Name:      "golden-snapshot",
+				Namespace: (func() *string { str := "golden-images"; return &str })(),
+			},
 		},
 		ContentType: cdiv1.DataVolumeContentType("content_type"),
 	}
@@ -106,6 +125,22 @@ func GetBaseOutputForDataVolume() interface{} {
 						"selector":           test_entities.LabelSelectorTerraform,
 						"volume_name":        "volume_name",
 						"storage_class_name": "standard",
+						"volume_mode":        "Block",
+						"data_source": []interface{}{
+							map[string]interface{}{
+								"api_group": "snapshot.storage.k8s.io",
+								"kind":      "VolumeSnapshot",
+								"name":      "golden-snapshot",
+							},
+						},
+						"data_source_ref": []interface{}{
+							map[string]interface{}{
+								"api_group": "snapshot.storage.k8s.io",
+								"kind":      "VolumeSnapshot",
+								"name":      "golden-snapshot",
+								"namespace": "golden-images",
+							},
+						},
 					},
 				},
 				"source": []interface{}{
@@ -123,6 +158,14 @@ func GetBaseOutputForDataVolume() interface{} {
 								"name":      "name",
 							},
 						},
+						"registry": []interface{}{
+							map[string]interface{}{
+								"url":             "docker://registry.example.com/golden/fedora:38",
+								"pull_method":     "node",
+								"secret_ref":      "registry-credentials",
+								"cert_config_map": "registry-ca",
+							},
+						},
 					},
 				},
 				"content_type": "content_type",
@@ -184,6 +227,117 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 							},
 						},
 					},
+					{
+						Name: "containerdisk",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							ContainerDisk: &kubevirtapiv1.ContainerDiskSource{
+								Image:           "quay.io/containerdisks/fedora:latest",
+								ImagePullPolicy: "Always",
+								ImagePullSecret: "image_pull_secret",
+								Path:            "/disk/fedora.qcow2",
+							},
+						},
+					},
+					{
+						Name: "cloudinit",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							CloudInitNoCloud: &kubevirtapiv1.CloudInitNoCloudSource{
+								UserData:    "#cloud-config",
+								NetworkData: "network_data",
+							},
+						},
+					},
+					{
+						Name: "pvc",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							PersistentVolumeClaim: &kubevirtapiv1.PersistentVolumeClaimVolumeSource{
+								PersistentVolumeClaimVolumeSource: k8sv1.PersistentVolumeClaimVolumeSource{
+									ClaimName: "claim",
+									ReadOnly:  true,
+								},
+								Hotpluggable: true,
+							},
+						},
+					},
+					{
+						Name: "ephemeral",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							Ephemeral: &kubevirtapiv1.EphemeralVolumeSource{
+								PersistentVolumeClaim: &k8sv1.PersistentVolumeClaimVolumeSource{
+									ClaimName: "base",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					{
+						Name: "scratch",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							EmptyDisk: &kubevirtapiv1.EmptyDiskSource{
+								Capacity: resource.MustParse("2Gi"),
+							},
+						},
+					},
+					{
+						Name: "hostdisk",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							HostDisk: &kubevirtapiv1.HostDisk{
+								Path:     "/data/disk.img",
+								Type:     "DiskOrCreate",
+								Capacity: resource.MustParse("1Gi"),
+								Shared:   (func() *bool { b := true; return &b })(),
+							},
+						},
+					},
+					{
+						Name: "configmap",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							ConfigMap: &kubevirtapiv1.ConfigMapVolumeSource{
+								LocalObjectReference: k8sv1.LocalObjectReference{Name: "app-config"},
+								Optional:             (func() *bool { b := true; return &b })(),
+								VolumeLabel:          "cfgdata",
+							},
+						},
+					},
+					{
+						Name: "secret",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							Secret: &kubevirtapiv1.SecretVolumeSource{
+								SecretName: "app-secret",
+							},
+						},
+					},
+					{
+						Name: "downwardapi",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							DownwardAPI: &kubevirtapiv1.DownwardAPIVolumeSource{
+								Fields: []k8sv1.DownwardAPIVolumeFile{
+									{
+										Path: "labels",
+										FieldRef: &k8sv1.ObjectFieldSelector{
+											APIVersion: "v1",
+											FieldPath:  "metadata.labels",
+										},
+										Mode: (func() *int32 { m := int32(0644); return &m })(),
+									},
+									{
+										Path: "memory",
+										ResourceFieldRef: &k8sv1.ResourceFieldSelector{
+											ContainerName: "compute",
+											Resource:      "limits.memory",
+											Divisor:       resource.MustParse("1Mi"),
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name: "metrics",
+						VolumeSource: kubevirtapiv1.VolumeSource{
+							DownwardMetrics: &kubevirtapiv1.DownwardMetricsVolumeSource{},
+						},
+					},
 				},
 				Domain: kubevirtapiv1.DomainSpec{
 					Resources: kubevirtapiv1.ResourceRequirements{
@@ -197,6 +351,120 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 						},
 						OvercommitGuestOverhead: true,
 					},
+					Memory: &kubevirtapiv1.Memory{
+						Guest: (func() *resource.Quantity { res, _ := resource.ParseQuantity("10G"); return &res })(),
+						Hugepages: &kubevirtapiv1.Hugepages{
+							PageSize: "2Mi",
+						},
+					},
+					Machine: &kubevirtapiv1.Machine{
+						Type: "q35",
+					},
+					Firmware: &kubevirtapiv1.Firmware{
+						UUID:   "5d307ca9-b3ef-428c-8861-06e72d69f223",
+						Serial: "e4686d2c-6e8d-4335-b8fd-81bee22f4814",
+						Bootloader: &kubevirtapiv1.Bootloader{
+							EFI: &kubevirtapiv1.EFI{
+								SecureBoot: (func() *bool { b := true; return &b })(),
+							},
+						},
+						KernelBoot: &kubevirtapiv1.KernelBoot{
+							KernelArgs: "console=ttyS0",
+							Container: &kubevirtapiv1.KernelBootContainer{
+								Image:           "quay.io/kubevirt/alpine-ext-kernel-boot-demo",
+								ImagePullSecret: "image_pull_secret",
+								ImagePullPolicy: "IfNotPresent",
+								KernelPath:      "/boot/vmlinuz-virt",
+								InitrdPath:      "/boot/initramfs-virt",
+							},
+						},
+					},
+					Clock: &kubevirtapiv1.Clock{
+						ClockOffset: kubevirtapiv1.ClockOffset{
+							UTC: &kubevirtapiv1.ClockOffsetUTC{
+								OffsetSeconds: (func() *int { i := 3600; return &i })(),
+							},
+						},
+						Timer: &kubevirtapiv1.Timer{
+							HPET: &kubevirtapiv1.HPETTimer{
+								Enabled:    (func() *bool { b := false; return &b })(),
+								TickPolicy: "delay",
+							},
+							RTC: &kubevirtapiv1.RTCTimer{
+								Enabled:    (func() *bool { b := true; return &b })(),
+								TickPolicy: "catchup",
+								Track:      "guest",
+							},
+							Hyperv: &kubevirtapiv1.HypervTimer{
+								Enabled: (func() *bool { b := true; return &b })(),
+							},
+						},
+					},
+					Features: &kubevirtapiv1.Features{
+						ACPI: kubevirtapiv1.FeatureState{
+							Enabled: (func() *bool { b := true; return &b })(),
+						},
+						APIC: &kubevirtapiv1.FeatureAPIC{
+							Enabled: (func() *bool { b := true; return &b })(),
+						},
+						Hyperv: &kubevirtapiv1.FeatureHyperv{
+							Relaxed: &kubevirtapiv1.FeatureState{
+								Enabled: (func() *bool { b := true; return &b })(),
+							},
+							VAPIC: &kubevirtapiv1.FeatureState{
+								Enabled: (func() *bool { b := true; return &b })(),
+							},
+							Spinlocks: &kubevirtapiv1.FeatureSpinlocks{
+								Enabled: (func() *bool { b := true; return &b })(),
+								Retries: (func() *uint32 { i := uint32(8191); return &i })(),
+							},
+							VPIndex: &kubevirtapiv1.FeatureState{
+								Enabled: (func() *bool { b := true; return &b })(),
+							},
+							SyNIC: &kubevirtapiv1.FeatureState{
+								Enabled: (func() *bool { b := true; return &b })(),
+							},
+							SyNICTimer: &kubevirtapiv1.SyNICTimer{
+								Enabled: (func() *bool { b := true; return &b })(),
+								Direct: &kubevirtapiv1.FeatureState{
+									Enabled: (func() *bool { b := true; return &b })(),
+								},
+							},
+							IPI: &kubevirtapiv1.FeatureState{
+								Enabled: (func() *bool { b := true; return &b })(),
+							},
+						},
+						SMM: &kubevirtapiv1.FeatureState{
+							Enabled: (func() *bool { b := true; return &b })(),
+						},
+						KVM: &kubevirtapiv1.FeatureKVM{
+							Hidden: true,
+						},
+					},
+					CPU: &kubevirtapiv1.CPU{
+						Cores:   2,
+						Sockets: 1,
+						Threads: 2,
+						Model:   "host-passthrough",
+						Features: []kubevirtapiv1.CPUFeature{
+							{
+								Name:   "pcid",
+								Policy: "require",
+							},
+							{
+								Name:   "svm",
+								Policy: "forbid",
+							},
+						},
+						DedicatedCPUPlacement: true,
+						IsolateEmulatorThread: true,
+						NUMA: &kubevirtapiv1.NUMA{
+							GuestMappingPassthrough: &kubevirtapiv1.NUMAGuestMappingPassthrough{},
+						},
+						Realtime: &kubevirtapiv1.Realtime{
+							Mask: "0-3,^1",
+						},
+					},
 					Devices: kubevirtapiv1.Devices{
 						Disks: []kubevirtapiv1.Disk{
 							{
@@ -209,6 +477,41 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 										PciAddress: "pci_address",
 									},
 								},
+								BootOrder:         (func() *uint { i := uint(1); return &i })(),
+								DedicatedIOThread: (func() *bool { b := true; return &b })(),
+								Cache:             "none",
+								IO:                "native",
+								BlockSize: &kubevirtapiv1.BlockSize{
+									Custom: &kubevirtapiv1.CustomBlockSize{
+										Logical:  512,
+										Physical: 4096,
+									},
+								},
+							},
+							{
+								Name: "cdrom",
+								DiskDevice: kubevirtapiv1.DiskDevice{
+									CDRom: &kubevirtapiv1.CDRomTarget{
+										Bus:      "sata",
+										ReadOnly: (func() *bool { b := true; return &b })(),
+										Tray:     "closed",
+									},
+								},
+								BootOrder: (func() *uint { i := uint(2); return &i })(),
+								BlockSize: &kubevirtapiv1.BlockSize{
+									MatchVolume: &kubevirtapiv1.FeatureState{
+										Enabled: (func() *bool { b := true; return &b })(),
+									},
+								},
+							},
+							{
+								Name: "lun",
+								DiskDevice: kubevirtapiv1.DiskDevice{
+									LUN: &kubevirtapiv1.LunTarget{
+										Bus: "scsi",
+									},
+								},
+								Shareable: (func() *bool { b := true; return &b })(),
 							},
 						},
 						Interfaces: []kubevirtapiv1.Interface{
@@ -217,13 +520,112 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 								InterfaceBindingMethod: kubevirtapiv1.InterfaceBindingMethod{
 									Bridge: &kubevirtapiv1.InterfaceBridge{},
 								},
+								Model:      "virtio",
+								MacAddress: "de:ad:00:00:be:af",
+								Ports:      []kubevirtapiv1.Port{},
+								PciAddress: "0000:81:01.10",
+								BootOrder:  (func() *uint { i := uint(3); return &i })(),
+								DHCPOptions: &kubevirtapiv1.DHCPOptions{
+									BootFileName:   "pxelinux.0",
+									TFTPServerName: "tftp.example.com",
+									NTPServers:     []string{"192.168.0.1"},
+									PrivateOptions: []kubevirtapiv1.DHCPPrivateOptions{
+										{
+											Option: 240,
+											Value:  "extra.options.kubevirt.io",
+										},
+									},
+								},
+								Tag:       "tag",
+								ACPIIndex: 1,
+							},
+							{
+								Name: "secondary",
+								InterfaceBindingMethod: kubevirtapiv1.InterfaceBindingMethod{
+									Masquerade: &kubevirtapiv1.InterfaceMasquerade{},
+								},
+								Ports: []kubevirtapiv1.Port{
+									{
+										Name:     "http",
+										Protocol: "TCP",
+										Port:     80,
+									},
+								},
 							},
 						},
+						GPUs: []kubevirtapiv1.GPU{
+							{
+								Name:       "gpu1",
+								DeviceName: "nvidia.com/GRID_T4-1Q",
+								VirtualGPUOptions: &kubevirtapiv1.VGPUOptions{
+									Display: &kubevirtapiv1.VGPUDisplayOptions{
+										Enabled: (func() *bool { b := true; return &b })(),
+										RamFB:   &kubevirtapiv1.FeatureState{Enabled: (func() *bool { b := false; return &b })()},
+									},
+								},
+							},
+						},
+						HostDevices: []kubevirtapiv1.HostDevice{
+							{
+								Name:       "nic1",
+								DeviceName: "intel.com/qat",
+								Tag:        "accel",
+							},
+						},
+						Filesystems: []kubevirtapiv1.Filesystem{
+							{
+								Name:     "configmap",
+								Virtiofs: &kubevirtapiv1.FilesystemVirtiofs{},
+							},
+						},
+						TPM: &kubevirtapiv1.TPMDevice{},
+						Watchdog: &kubevirtapiv1.Watchdog{
+							Name: "watchdog",
+							WatchdogDevice: kubevirtapiv1.WatchdogDevice{
+								I6300ESB: &kubevirtapiv1.I6300ESBWatchdog{Action: "poweroff"},
+							},
+						},
+						Rng: &kubevirtapiv1.Rng{},
+						Sound: &kubevirtapiv1.SoundDevice{
+							Name:  "audio",
+							Model: "ac97",
+						},
+						Inputs: []kubevirtapiv1.Input{
+							{Name: "tablet", Type: "tablet", Bus: "virtio"},
+						},
+						NetworkInterfaceMultiQueue: (func() *bool { b := true; return &b })(),
+						AutoattachGraphicsDevice:   (func() *bool { b := false; return &b })(),
+						AutoattachVSOCK:            (func() *bool { b := true; return &b })(),
 					},
 				},
 				NodeSelector: map[string]string{
 					"node_selector_key": "node_selector_value",
 				},
+				LivenessProbe: &kubevirtapiv1.Probe{
+					Handler: kubevirtapiv1.Handler{
+						HTTPGet: &k8sv1.HTTPGetAction{
+							Path:   "/healthz",
+							Port:   intstr.FromInt(8080),
+							Scheme: "HTTPS",
+							HTTPHeaders: []k8sv1.HTTPHeader{
+								{Name: "X-Probe", Value: "liveness"},
+							},
+						},
+					},
+					InitialDelaySeconds: 120,
+					TimeoutSeconds:      5,
+					PeriodSeconds:       20,
+					SuccessThreshold:    1,
+					FailureThreshold:    3,
+				},
+				ReadinessProbe: &kubevirtapiv1.Probe{
+					Handler: kubevirtapiv1.Handler{
+						Exec: &k8sv1.ExecAction{
+							Command: []string{"systemctl", "is-active", "nginx"},
+						},
+					},
+					PeriodSeconds: 10,
+				},
 				Hostname:      "hostname",
 				Subdomain:     "subdomain",
 				SchedulerName: "scheduler_name",
@@ -236,10 +638,26 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 						Value:             "value",
 					},
 				},
+				TopologySpreadConstraints: []k8sv1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: k8sv1.ScheduleAnyway,
+						LabelSelector: &v1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app": "test",
+							},
+						},
+					},
+				},
 				EvictionStrategy: (func() *kubevirtapiv1.EvictionStrategy {
 					retval := kubevirtapiv1.EvictionStrategy("eviction_strategy")
 					return &retval
 				})(),
+				StartStrategy: (func() *kubevirtapiv1.StartStrategy {
+					retval := kubevirtapiv1.StartStrategyPaused
+					return &retval
+				})(),
 				TerminationGracePeriodSeconds: utils.PtrToInt64(int64(120)),
 				Networks: []kubevirtapiv1.Network{
 					{
@@ -253,6 +671,14 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 							},
 						},
 					},
+					{
+						Name: "secondary",
+						NetworkSource: kubevirtapiv1.NetworkSource{
+							Pod: &kubevirtapiv1.PodNetwork{
+								VMNetworkCIDR: "10.0.2.0/24",
+							},
+						},
+					},
 				},
 				DNSPolicy: k8sv1.DNSPolicy("dns_policy"),
 				DNSConfig: &k8sv1.PodDNSConfig{
@@ -263,6 +689,30 @@ func GetBaseInputForVirtualMachine() kubevirtapiv1.VirtualMachineSpec {
 						},
 					},
 				},
+				AccessCredentials: []kubevirtapiv1.AccessCredential{
+					{
+						SSHPublicKey: &kubevirtapiv1.SSHPublicKeyAccessCredential{
+							Source: kubevirtapiv1.SSHPublicKeyAccessCredentialSource{
+								Secret: &kubevirtapiv1.AccessCredentialSecretSource{SecretName: "ssh-keys"},
+							},
+							PropagationMethod: kubevirtapiv1.SSHPublicKeyAccessCredentialPropagationMethod{
+								QemuGuestAgent: &kubevirtapiv1.QemuGuestAgentSSHPublicKeyAccessCredentialPropagation{
+									Users: []string{"fedora"},
+								},
+							},
+						},
+					},
+					{
+						UserPassword: &kubevirtapiv1.UserPasswordAccessCredential{
+							Source: kubevirtapiv1.UserPasswordAccessCredentialSource{
+								Secret: &kubevirtapiv1.AccessCredentialSecretSource{SecretName: "passwords"},
+							},
+							PropagationMethod: kubevirtapiv1.UserPasswordAccessCredentialPropagationMethod{
+								QemuGuestAgent: &kubevirtapiv1.QemuGuestAgentUserPasswordAccessCredentialPropagation{},
+							},
+						},
+					},
+				},
 				Affinity: &k8sv1.Affinity{
 					NodeAffinity: &k8sv1.NodeAffinity{
 						RequiredDuringSchedulingIgnoredDuringExecution: &k8sv1.NodeSelector{
@@ -332,6 +782,22 @@ func GetBaseOutputForVirtualMachine() interface{} {
 								"selector":           test_entities.LabelSelectorTerraform,
 								"volume_name":        "volume_name",
 								"storage_class_name": "standard",
+								"volume_mode":        "Block",
+								"data_source": []interface{}{
+									map[string]interface{}{
+										"api_group": "snapshot.storage.k8s.io",
+										"kind":      "VolumeSnapshot",
+										"name":      "golden-snapshot",
+									},
+								},
+								"data_source_ref": []interface{}{
+									map[string]interface{}{
+										"api_group": "snapshot.storage.k8s.io",
+										"kind":      "VolumeSnapshot",
+										"name":      "golden-snapshot",
+										"namespace": "golden-images",
+									},
+								},
 							},
 						},
 						"source": []interface{}{
@@ -349,6 +815,14 @@ func GetBaseOutputForVirtualMachine() interface{} {
 										"name":      "name",
 									},
 								},
+								"registry": []interface{}{
+									map[string]interface{}{
+										"url":             "docker://registry.example.com/golden/fedora:38",
+										"pull_method":     "node",
+										"secret_ref":      "registry-credentials",
+										"cert_config_map": "registry-ca",
+									},
+								},
 							},
 						},
 						"content_type": "content_type",
@@ -391,10 +865,61 @@ func GetBaseOutputForVirtualMachine() interface{} {
 								"value":              "value",
 							},
 						},
+						"topology_spread_constraints": []interface{}{
+							map[string]interface{}{
+								"max_skew":           1,
+								"topology_key":       "topology.kubernetes.io/zone",
+								"when_unsatisfiable": "ScheduleAnyway",
+								"label_selector": []interface{}{
+									map[string]interface{}{
+										"match_labels": map[string]interface{}{
+											"app": "test",
+										},
+									},
+								},
+							},
+						},
 						"dns_policy":          "dns_policy",
 						"priority_class_name": "priority_class_name",
-						"hostname":            "hostname",
-						"subdomain":           "subdomain",
+						"liveness_probe": []interface{}{
+							map[string]interface{}{
+								"http_get": []interface{}{
+									map[string]interface{}{
+										"path":   "/healthz",
+										"port":   "8080",
+										"host":   "",
+										"scheme": "HTTPS",
+										"http_header": []interface{}{
+											map[string]interface{}{
+												"name":  "X-Probe",
+												"value": "liveness",
+											},
+										},
+									},
+								},
+								"initial_delay_seconds": 120,
+								"timeout_seconds":       5,
+								"period_seconds":        20,
+								"success_threshold":     1,
+								"failure_threshold":     3,
+							},
+						},
+						"readiness_probe": []interface{}{
+							map[string]interface{}{
+								"exec": []interface{}{
+									map[string]interface{}{
+										"command": []interface{}{"systemctl", "is-active", "nginx"},
+									},
+								},
+								"initial_delay_seconds": 0,
+								"timeout_seconds":       0,
+								"period_seconds":        10,
+								"success_threshold":     0,
+								"failure_threshold":     0,
+							},
+						},
+						"hostname":  "hostname",
+						"subdomain": "subdomain",
 						"pod_dns_config": []interface{}{
 							map[string]interface{}{
 								"option": []interface{}{
@@ -405,6 +930,54 @@ func GetBaseOutputForVirtualMachine() interface{} {
 								},
 							},
 						},
+						"access_credentials": []interface{}{
+							map[string]interface{}{
+								"ssh_public_key": []interface{}{
+									map[string]interface{}{
+										"source": []interface{}{
+											map[string]interface{}{
+												"secret": []interface{}{
+													map[string]interface{}{
+														"secret_name": "ssh-keys",
+													},
+												},
+											},
+										},
+										"propagation_method": []interface{}{
+											map[string]interface{}{
+												"qemu_guest_agent": []interface{}{
+													map[string]interface{}{
+														"users": []interface{}{"fedora"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"user_password": []interface{}{
+									map[string]interface{}{
+										"source": []interface{}{
+											map[string]interface{}{
+												"secret": []interface{}{
+													map[string]interface{}{
+														"secret_name": "passwords",
+													},
+												},
+											},
+										},
+										"propagation_method": []interface{}{
+											map[string]interface{}{
+												"qemu_guest_agent": []interface{}{
+													map[string]interface{}{},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
 						"affinity": []interface{}{
 							map[string]interface{}{
 								"node_affinity": []interface{}{
@@ -444,21 +1017,192 @@ func GetBaseOutputForVirtualMachine() interface{} {
 														},
 													},
 												},
-												"name":   "test-vm-datavolumedisk1",
-												"serial": "serial",
+												"name":                "test-vm-datavolumedisk1",
+												"serial":              "serial",
+												"boot_order":          1,
+												"dedicated_io_thread": true,
+												"cache":               "none",
+												"io":                  "native",
+												"block_size": []interface{}{
+													map[string]interface{}{
+														"custom": []interface{}{
+															map[string]interface{}{
+																"logical":  512,
+																"physical": 4096,
+															},
+														},
+													},
+												},
+												"shareable": false,
 											},
-										},
-										"interface": []interface{}{
 											map[string]interface{}{
-												"interface_binding_method": "InterfaceBridge",
-												"name":                     "main",
-											},
-										},
-									},
-								},
-								"resources": []interface{}{
-									map[string]interface{}{
-										"requests": map[string]interface{}{
+												"disk_device": []interface{}{
+													map[string]interface{}{
+														"cdrom": []interface{}{
+															map[string]interface{}{
+																"bus":       "sata",
+																"read_only": true,
+																"tray":      "closed",
+															},
+														},
+													},
+												},
+												"name":                "cdrom",
+												"serial":              "",
+												"boot_order":          2,
+												"dedicated_io_thread": false,
+												"cache":               "",
+												"io":                  "",
+												"block_size": []interface{}{
+													map[string]interface{}{
+														"match_volume": []interface{}{
+															map[string]interface{}{
+																"enabled": true,
+															},
+														},
+													},
+												},
+												"shareable": false,
+											},
+											map[string]interface{}{
+												"disk_device": []interface{}{
+													map[string]interface{}{
+														"lun": []interface{}{
+															map[string]interface{}{
+																"bus":       "scsi",
+																"read_only": false,
+															},
+														},
+													},
+												},
+												"name":                "lun",
+												"serial":              "",
+												"dedicated_io_thread": false,
+												"cache":               "",
+												"io":                  "",
+												"shareable":           true,
+											},
+										},
+										"interface": []interface{}{
+											map[string]interface{}{
+												"interface_binding_method": "InterfaceBridge",
+												"name":                     "main",
+												"model":                    "virtio",
+												"mac_address":              "de:ad:00:00:be:af",
+												"ports":                    []interface{}{},
+												"pci_address":              "0000:81:01.10",
+												"boot_order":               3,
+												"dhcp_options": []interface{}{
+													map[string]interface{}{
+														"boot_file_name":   "pxelinux.0",
+														"tftp_server_name": "tftp.example.com",
+														"ntp_servers":      []interface{}{"192.168.0.1"},
+														"private_options": []interface{}{
+															map[string]interface{}{
+																"option": 240,
+																"value":  "extra.options.kubevirt.io",
+															},
+														},
+													},
+												},
+												"tag":        "tag",
+												"acpi_index": 1,
+											},
+											map[string]interface{}{
+												"interface_binding_method": "InterfaceMasquerade",
+												"name":                     "secondary",
+												"model":                    "",
+												"mac_address":              "",
+												"ports": []interface{}{
+													map[string]interface{}{
+														"name":     "http",
+														"protocol": "TCP",
+														"port":     80,
+													},
+												},
+												"pci_address": "",
+												"tag":         "",
+												"acpi_index":  0,
+											},
+										},
+										"gpus": []interface{}{
+											map[string]interface{}{
+												"name":        "gpu1",
+												"device_name": "nvidia.com/GRID_T4-1Q",
+												"virtual_gpu_options": []interface{}{
+													map[string]interface{}{
+														"display": []interface{}{
+															map[string]interface{}{
+																"enabled": true,
+																"ram_fb": []interface{}{
+																	map[string]interface{}{
+																		"enabled": false,
+																	},
+																},
+															},
+														},
+													},
+												},
+												"tag": "",
+											},
+										},
+										"host_devices": []interface{}{
+											map[string]interface{}{
+												"name":        "nic1",
+												"device_name": "intel.com/qat",
+												"tag":         "accel",
+											},
+										},
+										"filesystem": []interface{}{
+											map[string]interface{}{
+												"name": "configmap",
+												"virtiofs": []interface{}{
+													map[string]interface{}{},
+												},
+											},
+										},
+										"tpm": []interface{}{
+											map[string]interface{}{},
+										},
+										"watchdog": []interface{}{
+											map[string]interface{}{
+												"name": "watchdog",
+												"i6300esb": []interface{}{
+													map[string]interface{}{
+														"action": "poweroff",
+													},
+												},
+											},
+										},
+										"rng": []interface{}{
+											map[string]interface{}{},
+										},
+										"sound": []interface{}{
+											map[string]interface{}{
+												"name":  "audio",
+												"model": "ac97",
+											},
+										},
+										"input": []interface{}{
+											map[string]interface{}{
+												"name": "tablet",
+												"type": "tablet",
+												"bus":  "virtio",
+											},
+										},
+										"use_virtio_transitional":      false,
+										"network_interface_multiqueue": true,
+										"block_multi_queue":            false,
+										"autoattach_graphics_device":   false,
+										"autoattach_serial_console":    true,
+										"autoattach_pod_interface":     true,
+										"autoattach_mem_balloon":       true,
+										"autoattach_vsock":             true,
+									},
+								},
+								"resources": []interface{}{
+									map[string]interface{}{
+										"requests": map[string]interface{}{
 											"cpu":    "4",
 											"memory": "10G",
 										},
@@ -469,9 +1213,181 @@ func GetBaseOutputForVirtualMachine() interface{} {
 										"over_commit_guest_overhead": true,
 									},
 								},
+								"memory": []interface{}{
+									map[string]interface{}{
+										"guest": "10G",
+										"hugepages": []interface{}{
+											map[string]interface{}{
+												"page_size": "2Mi",
+											},
+										},
+									},
+								},
+								"machine": []interface{}{
+									map[string]interface{}{
+										"type": "q35",
+									},
+								},
+								"firmware": []interface{}{
+									map[string]interface{}{
+										"uuid":   "5d307ca9-b3ef-428c-8861-06e72d69f223",
+										"serial": "e4686d2c-6e8d-4335-b8fd-81bee22f4814",
+										"bootloader": []interface{}{
+											map[string]interface{}{
+												"efi": []interface{}{
+													map[string]interface{}{
+														"secure_boot": true,
+													},
+												},
+											},
+										},
+										"kernel_boot": []interface{}{
+											map[string]interface{}{
+												"kernel_args": "console=ttyS0",
+												"container": []interface{}{
+													map[string]interface{}{
+														"image":             "quay.io/kubevirt/alpine-ext-kernel-boot-demo",
+														"image_pull_secret": "image_pull_secret",
+														"image_pull_policy": "IfNotPresent",
+														"kernel_path":       "/boot/vmlinuz-virt",
+														"initrd_path":       "/boot/initramfs-virt",
+													},
+												},
+											},
+										},
+									},
+								},
+								"clock": []interface{}{
+									map[string]interface{}{
+										"utc": []interface{}{
+											map[string]interface{}{
+												"offset_seconds": 3600,
+											},
+										},
+										"timer": []interface{}{
+											map[string]interface{}{
+												"hpet": []interface{}{
+													map[string]interface{}{
+														"enabled":     false,
+														"tick_policy": "delay",
+													},
+												},
+												"rtc": []interface{}{
+													map[string]interface{}{
+														"enabled":     true,
+														"tick_policy": "catchup",
+														"track":       "guest",
+													},
+												},
+												"hyperv": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+													},
+												},
+											},
+										},
+									},
+								},
+								"features": []interface{}{
+									map[string]interface{}{
+										"acpi": []interface{}{
+											map[string]interface{}{
+												"enabled": true,
+											},
+										},
+										"apic": []interface{}{
+											map[string]interface{}{
+												"enabled":          true,
+												"end_of_interrupt": false,
+											},
+										},
+										"hyperv": []interface{}{
+											map[string]interface{}{
+												"relaxed": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+													},
+												},
+												"vapic": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+													},
+												},
+												"spinlocks": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+														"retries": 8191,
+													},
+												},
+												"vpindex": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+													},
+												},
+												"synic": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+													},
+												},
+												"stimer": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+														"direct":  true,
+													},
+												},
+												"ipi": []interface{}{
+													map[string]interface{}{
+														"enabled": true,
+													},
+												},
+											},
+										},
+										"smm": []interface{}{
+											map[string]interface{}{
+												"enabled": true,
+											},
+										},
+										"kvm": []interface{}{
+											map[string]interface{}{
+												"hidden": true,
+											},
+										},
+									},
+								},
+								"cpu": []interface{}{
+									map[string]interface{}{
+										"cores":   2,
+										"sockets": 1,
+										"threads": 2,
+										"model":   "host-passthrough",
+										"feature": []interface{}{
+											map[string]interface{}{
+												"name":   "pcid",
+												"policy": "require",
+											},
+											map[string]interface{}{
+												"name":   "svm",
+												"policy": "forbid",
+											},
+										},
+										"dedicated_cpu_placement": true,
+										"isolate_emulator_thread": true,
+										"numa": []interface{}{
+											map[string]interface{}{
+												"guest_mapping_passthrough": true,
+											},
+										},
+										"realtime": []interface{}{
+											map[string]interface{}{
+												"mask": "0-3,^1",
+											},
+										},
+									},
+								},
 							},
 						},
 						"eviction_strategy":                "eviction_strategy",
+						"start_strategy":                   "Paused",
 						"termination_grace_period_seconds": int64(120),
 						"volume": []interface{}{
 							map[string]interface{}{
@@ -509,6 +1425,166 @@ func GetBaseOutputForVirtualMachine() interface{} {
 									},
 								},
 							},
+							map[string]interface{}{
+								"name": "containerdisk",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"container_disk": []interface{}{
+											map[string]interface{}{
+												"image":             "quay.io/containerdisks/fedora:latest",
+												"image_pull_policy": "Always",
+												"image_pull_secret": "image_pull_secret",
+												"path":              "/disk/fedora.qcow2",
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "cloudinit",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"cloud_init_no_cloud": []interface{}{
+											map[string]interface{}{
+												"user_data_base64":    "",
+												"user_data":           "#cloud-config",
+												"network_data_base64": "",
+												"network_data":        "network_data",
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "pvc",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"persistent_volume_claim": []interface{}{
+											map[string]interface{}{
+												"claim_name":   "claim",
+												"read_only":    true,
+												"hotpluggable": true,
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "ephemeral",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"ephemeral": []interface{}{
+											map[string]interface{}{
+												"persistent_volume_claim": []interface{}{
+													map[string]interface{}{
+														"claim_name": "base",
+														"read_only":  true,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "scratch",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"empty_disk": []interface{}{
+											map[string]interface{}{
+												"capacity": "2Gi",
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "hostdisk",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"host_disk": []interface{}{
+											map[string]interface{}{
+												"path":     "/data/disk.img",
+												"type":     "DiskOrCreate",
+												"capacity": "1Gi",
+												"shared":   true,
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "configmap",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"config_map": []interface{}{
+											map[string]interface{}{
+												"name":         "app-config",
+												"optional":     true,
+												"volume_label": "cfgdata",
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "secret",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"secret": []interface{}{
+											map[string]interface{}{
+												"secret_name":  "app-secret",
+												"optional":     false,
+												"volume_label": "",
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "downwardapi",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"downward_api": []interface{}{
+											map[string]interface{}{
+												"field": []interface{}{
+													map[string]interface{}{
+														"path": "labels",
+														"field_ref": []interface{}{
+															map[string]interface{}{
+																"api_version": "v1",
+																"field_path":  "metadata.labels",
+															},
+														},
+														"mode": 0644,
+													},
+													map[string]interface{}{
+														"path": "memory",
+														"resource_field_ref": []interface{}{
+															map[string]interface{}{
+																"container_name": "compute",
+																"resource":       "limits.memory",
+																"divisor":        "1Mi",
+															},
+														},
+													},
+												},
+												"volume_label": "",
+											},
+										},
+									},
+								},
+							},
+							map[string]interface{}{
+								"name": "metrics",
+								"volume_source": []interface{}{
+									map[string]interface{}{
+										"downward_metrics": []interface{}{
+											map[string]interface{}{},
+										},
+									},
+								},
+							},
 						},
 						"network": []interface{}{
 							map[string]interface{}{
@@ -529,6 +1605,18 @@ func GetBaseOutputForVirtualMachine() interface{} {
 									},
 								},
 							},
+							map[string]interface{}{
+								"name": "secondary",
+								"network_source": []interface{}{
+									map[string]interface{}{
+										"pod": []interface{}{
+											map[string]interface{}{
+												"vm_network_cidr": "10.0.2.0/24",
+											},
+										},
+									},
+								},
+							},
 						},
 					},
 				},
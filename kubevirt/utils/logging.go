@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	kubevirtapiv1 "kubevirt.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Logging subsystems, which can be tuned separately through TF_LOG_PROVIDER_KUBEVIRT_<SUBSYSTEM>.
+const (
+	LogSubsystemClient = "client"
+	LogSubsystemWait   = "wait"
+	LogSubsystemPatch  = "patch"
+)
+
+const redactedValue = "***"
+
+var logSubsystems = []string{LogSubsystemClient, LogSubsystemWait, LogSubsystemPatch}
+
+// sensitiveLogFieldKeys are log fields whose values are always masked.
+var sensitiveLogFieldKeys = []string{
+	"user_data",
+	"user_data_base64",
+	"network_data",
+	"network_data_base64",
+	"password",
+	"token",
+}
+
+// sensitiveLogRegexes match credentials in log messages and string field values.
+var sensitiveLogRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`),
+}
+
+// lastAppliedConfigAnnotation holds a full copy of the object, including any secrets in it.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// NewLogContext returns a context whose root and subsystem loggers tag every line with the
+// namespace and name of the object being worked on, and mask sensitive values.
+func NewLogContext(ctx context.Context, namespace, name string) context.Context {
+	ctx = tflog.SetField(ctx, "namespace", namespace)
+	ctx = tflog.SetField(ctx, "name", name)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, sensitiveLogFieldKeys...)
+	ctx = tflog.MaskLogRegexes(ctx, sensitiveLogRegexes...)
+	for _, subsystem := range logSubsystems {
+		ctx = tflog.NewSubsystem(ctx, subsystem, tflog.WithRootFields())
+		ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystem, sensitiveLogFieldKeys...)
+		ctx = tflog.SubsystemMaskLogRegexes(ctx, subsystem, sensitiveLogRegexes...)
+	}
+	return ctx
+}
+
+// LogJSON renders an object as JSON for logging. Unlike %#v it shows the values of pointer fields
+// instead of their addresses.
+func LogJSON(obj interface{}) string {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Sprintf("%+v", obj)
+	}
+	return string(data)
+}
+
+// RedactVirtualMachine returns a copy of the virtual machine that is safe to log, with
+// cloud-init payloads and data volume source credentials masked.
+func RedactVirtualMachine(vm *kubevirtapiv1.VirtualMachine) *kubevirtapiv1.VirtualMachine {
+	if vm == nil {
+		return nil
+	}
+	result := vm.DeepCopy()
+	redactAnnotations(result.Annotations)
+	if result.Spec.Template != nil {
+		redactVolumes(result.Spec.Template.Spec.Volumes)
+	}
+	for i := range result.Spec.DataVolumeTemplates {
+		redactDataVolumeSource(result.Spec.DataVolumeTemplates[i].Spec.Source)
+	}
+	return result
+}
+
+// RedactDataVolume returns a copy of the data volume that is safe to log, with source
+// credentials masked.
+func RedactDataVolume(dv *cdiv1.DataVolume) *cdiv1.DataVolume {
+	if dv == nil {
+		return nil
+	}
+	result := dv.DeepCopy()
+	redactAnnotations(result.Annotations)
+	redactDataVolumeSource(result.Spec.Source)
+	return result
+}
+
+func redactAnnotations(annotations map[string]string) {
+	if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+		annotations[lastAppliedConfigAnnotation] = redactedValue
+	}
+}
+
+func redactVolumes(volumes []kubevirtapiv1.Volume) {
+	for i := range volumes {
+		if source := volumes[i].CloudInitConfigDrive; source != nil {
+			source.UserData = redactString(source.UserData)
+			source.UserDataBase64 = redactString(source.UserDataBase64)
+			source.NetworkData = redactString(source.NetworkData)
+			source.NetworkDataBase64 = redactString(source.NetworkDataBase64)
+		}
+		if source := volumes[i].CloudInitNoCloud; source != nil {
+			source.UserData = redactString(source.UserData)
+			source.UserDataBase64 = redactString(source.UserDataBase64)
+			source.NetworkData = redactString(source.NetworkData)
+			source.NetworkDataBase64 = redactString(source.NetworkDataBase64)
+		}
+	}
+}
+
+func redactDataVolumeSource(source *cdiv1.DataVolumeSource) {
+	if source == nil {
+		return
+	}
+	if source.HTTP != nil {
+		source.HTTP.URL = redactURL(source.HTTP.URL)
+		for i, header := range source.HTTP.ExtraHeaders {
+			source.HTTP.ExtraHeaders[i] = redactHeader(header)
+		}
+	}
+	if source.S3 != nil {
+		source.S3.URL = redactURL(source.S3.URL)
+	}
+}
+
+func redactString(s string) string {
+	if s == "" {
+		return s
+	}
+	return redactedValue
+}
+
+// redactURL masks the password of credentials embedded in a URL.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+	return u.String()
+}
+
+// redactHeader keeps the name of an HTTP header and masks its value.
+func redactHeader(header string) string {
+	name, _, found := strings.Cut(header, ":")
+	if !found {
+		return redactedValue
+	}
+	return name + ": " + redactedValue
+}
@@ -74,6 +74,15 @@ func (po PatchOperations) MarshalJSON() ([]byte, error) {
 	return json.Marshal(v)
 }
 
+// Paths returns the paths the operations apply to, which, unlike the values, are safe to log.
+func (po PatchOperations) Paths() []string {
+	paths := make([]string, 0, len(po))
+	for _, op := range po {
+		paths = append(paths, op.GetPath())
+	}
+	return paths
+}
+
 func (po PatchOperations) Equal(ops []PatchOperation) bool {
 	var v []PatchOperation = po
 
@@ -152,3 +161,24 @@ func (o *RemoveOperation) String() string {
 	b, _ := o.MarshalJSON()
 	return string(b)
 }
+
+// TestOperation makes the whole patch fail unless the value at Path equals Value.
+type TestOperation struct {
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+	Op    string      `json:"op"`
+}
+
+func (o *TestOperation) GetPath() string {
+	return o.Path
+}
+
+func (o *TestOperation) MarshalJSON() ([]byte, error) {
+	o.Op = "test"
+	return json.Marshal(*o)
+}
+
+func (o *TestOperation) String() string {
+	b, _ := o.MarshalJSON()
+	return string(b)
+}
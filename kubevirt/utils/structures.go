@@ -3,14 +3,62 @@ package utils
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	api "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ResourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff.
+type ResourceGetter interface {
+	Get(key string) interface{}
+}
+
+// RawConfigGetter is implemented by both schema.ResourceData and schema.ResourceDiff.
+type RawConfigGetter interface {
+	GetRawConfig() cty.Value
+}
+
+// ConfigValue returns the value configured under key, e.g. "spec.0.template.0.spec.0.volume",
+// or a null value when nothing is configured there.
+func ConfigValue(config cty.Value, key string) cty.Value {
+	value := config
+	for _, part := range strings.Split(key, ".") {
+		if value.IsNull() || !value.IsKnown() {
+			return value
+		}
+		ty := value.Type()
+		if index, err := strconv.Atoi(part); err == nil {
+			if !(ty.IsListType() || ty.IsTupleType()) || index >= value.LengthInt() {
+				return cty.NullVal(cty.DynamicPseudoType)
+			}
+			value = value.Index(cty.NumberIntVal(int64(index)))
+			continue
+		}
+		if !ty.IsObjectType() || !ty.HasAttribute(part) {
+			return cty.NullVal(cty.DynamicPseudoType)
+		}
+		value = value.GetAttr(part)
+	}
+	return value
+}
+
+// ConfigKnown reports whether the values configured under the given keys are all known, that is
+// none of them depends on a value that is only known after apply.
+func ConfigKnown(resourceData RawConfigGetter, keys ...string) bool {
+	config := resourceData.GetRawConfig()
+	for _, key := range keys {
+		if !ConfigValue(config, key).IsWhollyKnown() {
+			return false
+		}
+	}
+	return true
+}
+
 func IdParts(id string) (string, string, error) {
 	parts := strings.Split(id, "/")
 	if len(parts) != 2 {
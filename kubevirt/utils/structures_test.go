@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"gotest.tools/assert"
+)
+
+func TestConfigValue(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"spec": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"known":   cty.StringVal("value"),
+				"unknown": cty.UnknownVal(cty.String),
+				"empty":   cty.ListValEmpty(cty.String),
+			}),
+		}),
+	})
+
+	cases := []struct {
+		key   string
+		null  bool
+		known bool
+	}{
+		{key: "spec.0.known", known: true},
+		{key: "spec.0.unknown", known: false},
+		{key: "spec", known: false},
+		{key: "spec.0.empty", known: true},
+		{key: "spec.0.empty.0", null: true, known: true},
+		{key: "spec.1.known", null: true, known: true},
+		{key: "spec.0.missing", null: true, known: true},
+		{key: "spec.0.unknown.0", known: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			value := ConfigValue(config, tc.key)
+			assert.Equal(t, tc.null, value.IsKnown() && value.IsNull())
+			assert.Equal(t, tc.known, value.IsWhollyKnown())
+		})
+	}
+}
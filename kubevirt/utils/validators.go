@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -155,7 +156,7 @@ func validateResourceList(value interface{}, key string) (ws []string, es []erro
 	return
 }
 
-func validateResourceQuantity(value interface{}, key string) (ws []string, es []error) {
+func ValidateResourceQuantity(value interface{}, key string) (ws []string, es []error) {
 	if v, ok := value.(string); ok {
 		_, err := resource.ParseQuantity(v)
 		if err != nil {
@@ -165,6 +166,20 @@ func validateResourceQuantity(value interface{}, key string) (ws []string, es []
 	return
 }
 
+// ValidateDeviceResourceName checks that the value is a device plugin resource name, a qualified
+// name with a vendor domain prefix, such as nvidia.com/GP102GL_Tesla_P40.
+func ValidateDeviceResourceName(value interface{}, key string) (ws []string, es []error) {
+	v := value.(string)
+	if !strings.Contains(v, "/") {
+		es = append(es, fmt.Errorf("%s (%q) must be prefixed with the vendor domain of the device plugin, such as nvidia.com/", key, v))
+		return
+	}
+	for _, err := range utilValidation.IsQualifiedName(v) {
+		es = append(es, fmt.Errorf("%s (%q) %s", key, v, err))
+	}
+	return
+}
+
 func validateNonNegativeInteger(value interface{}, key string) (ws []string, es []error) {
 	v := value.(int)
 	if v < 0 {
@@ -310,3 +325,18 @@ func StringIsIntInRange(min int, max int) schema.SchemaValidateFunc {
 		return warnings, errors
 	}
 }
+
+func ValidateDuration(value interface{}, key string) (ws []string, es []error) {
+	v, ok := value.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+	if v == "" {
+		return
+	}
+	if _, err := time.ParseDuration(v); err != nil {
+		es = append(es, fmt.Errorf("%s: cannot parse '%s' as duration: %s", key, v, err))
+	}
+	return
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestValidateDeviceResourceName(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{value: "nvidia.com/GP102GL_Tesla_P40", valid: true},
+		{value: "intel.com/qat", valid: true},
+		{value: "GP102GL_Tesla_P40", valid: false},
+		{value: "Nvidia.com/GP102GL", valid: false},
+		{value: "nvidia.com/", valid: false},
+		{value: "nvidia.com/GP102GL Tesla", valid: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			_, es := ValidateDeviceResourceName(tc.value, "device_name")
+			if tc.valid && len(es) > 0 {
+				t.Errorf("expected %q to be valid, got %v", tc.value, es)
+			}
+			if !tc.valid && len(es) == 0 {
+				t.Errorf("expected %q to be invalid", tc.value)
+			}
+		})
+	}
+}